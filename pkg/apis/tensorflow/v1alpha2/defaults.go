@@ -0,0 +1,29 @@
+// Copyright 2018 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha2
+
+// IsChieflessJob reports whether tfJob declares neither a Chief nor a
+// Master replica, whatever other replica types it has. Such jobs have no
+// replica whose success alone signals completion, so the controller
+// treats worker:0 as the implicit chief for completion-tracking purposes.
+func IsChieflessJob(tfJob *TFJob) bool {
+	if _, ok := tfJob.Spec.TFReplicaSpecs[TFReplicaTypeChief]; ok {
+		return false
+	}
+	if _, ok := tfJob.Spec.TFReplicaSpecs[TFReplicaTypeMaster]; ok {
+		return false
+	}
+	return true
+}
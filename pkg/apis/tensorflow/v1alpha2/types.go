@@ -0,0 +1,289 @@
+// Copyright 2018 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha2
+
+import (
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DefaultPortName is the name of the port used to communicate between replicas.
+	DefaultPortName = "tfjob-port"
+	// DefaultContainerName is the name of the container that runs TensorFlow.
+	DefaultContainerName = "tensorflow"
+	// DefaultPort is the default port used to communicate between replicas.
+	DefaultPort = 2222
+	// DefaultRestartPolicy is the default restart policy applied to a replica
+	// when its spec does not set one explicitly.
+	DefaultRestartPolicy = RestartPolicyNever
+)
+
+// TFReplicaType is the type of TFReplica, i.e. the role a replica plays in a
+// distributed training job.
+type TFReplicaType string
+
+const (
+	// TFReplicaTypePS is the type for parameter servers.
+	TFReplicaTypePS TFReplicaType = "PS"
+	// TFReplicaTypeWorker is the type for workers. Workers do the training.
+	TFReplicaTypeWorker TFReplicaType = "Worker"
+	// TFReplicaTypeChief is the type for the chief, which coordinates training.
+	TFReplicaTypeChief TFReplicaType = "Chief"
+	// TFReplicaTypeMaster is an alias for TFReplicaTypeChief kept for
+	// backwards compatibility with older TFJob specs.
+	TFReplicaTypeMaster TFReplicaType = "Master"
+	// TFReplicaTypeEval is the type for evaluator replicas.
+	TFReplicaTypeEval TFReplicaType = "Evaluator"
+)
+
+// RestartPolicy describes how the replicas of a TFReplicaSpec should be
+// restarted when a pod exits.
+type RestartPolicy string
+
+const (
+	// RestartPolicyAlways always restarts the pod after it exits.
+	RestartPolicyAlways RestartPolicy = "Always"
+	// RestartPolicyOnFailure only restarts the pod when it fails.
+	RestartPolicyOnFailure RestartPolicy = "OnFailure"
+	// RestartPolicyNever never restarts the pod.
+	RestartPolicyNever RestartPolicy = "Never"
+	// RestartPolicyExitCode restarts the pod based on the exit code reported
+	// by the tensorflow container: a zero exit code is treated as success, a
+	// small set of well-known codes (130, 137, 143) sent by Kubernetes itself
+	// when evicting or OOM-killing a pod are treated as transient and
+	// retried, and anything else is treated as a permanent failure.
+	RestartPolicyExitCode RestartPolicy = "ExitCode"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TFJob represents the configuration of a single distributed TensorFlow job.
+type TFJob struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TFJobSpec   `json:"spec,omitempty"`
+	Status TFJobStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TFJobList is a list of TFJobs.
+type TFJobList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []TFJob `json:"items"`
+}
+
+// CleanPodPolicy determines which pods the controller deletes once a TFJob
+// reaches a terminal state.
+type CleanPodPolicy string
+
+const (
+	// CleanPodPolicyAll deletes every pod once the job finishes.
+	CleanPodPolicyAll CleanPodPolicy = "All"
+	// CleanPodPolicyRunning deletes only pods that are still running
+	// (Pending/Running) once the job finishes, leaving completed/failed
+	// pods around for inspection.
+	CleanPodPolicyRunning CleanPodPolicy = "Running"
+	// CleanPodPolicyNone never deletes pods.
+	CleanPodPolicyNone CleanPodPolicy = "None"
+)
+
+// SuccessPolicy determines the criteria the controller uses to decide a
+// TFJob has completed successfully.
+type SuccessPolicy string
+
+const (
+	// SuccessPolicyDefault keeps the controller's original behavior: the
+	// chief/master replica succeeding (or, lacking one, worker:0
+	// succeeding) marks the job Succeeded.
+	SuccessPolicyDefault SuccessPolicy = "Default"
+	// SuccessPolicyAllWorkers requires every Worker replica to reach
+	// PodSucceeded before the job is marked Succeeded.
+	SuccessPolicyAllWorkers SuccessPolicy = "AllWorkers"
+)
+
+// FailurePolicy determines how the controller reacts when a single replica
+// of a TFReplicaSpec fails.
+type FailurePolicy string
+
+const (
+	// FailurePolicyRestartAll deletes every other pod owned by the TFJob so
+	// the whole distributed training run restarts together.
+	FailurePolicyRestartAll FailurePolicy = "RestartAll"
+	// FailurePolicyIgnore leaves the other replicas running and does not
+	// restart the failed pod itself.
+	FailurePolicyIgnore FailurePolicy = "Ignore"
+	// FailurePolicyFail immediately marks the TFJob Failed.
+	FailurePolicyFail FailurePolicy = "Fail"
+)
+
+// TFJobSpec is the spec for a TFJob resource.
+type TFJobSpec struct {
+	// CleanPodPolicy defines which pods the controller deletes once the
+	// TFJob reaches a terminal state. Defaults to Running.
+	CleanPodPolicy *CleanPodPolicy `json:"cleanPodPolicy,omitempty"`
+
+	// SuccessPolicy determines the criteria used to decide the TFJob has
+	// completed successfully. Defaults to Default.
+	SuccessPolicy *SuccessPolicy `json:"successPolicy,omitempty"`
+
+	// Suspend, when true, tells the controller to tear down every pod and
+	// service owned by this TFJob (per CleanPodPolicy) and wait, without
+	// deleting the TFJob itself. Flipping it back to false recreates the
+	// pods and restarts the job's StartTime. Defaults to false.
+	Suspend *bool `json:"suspend,omitempty"`
+
+	// ActiveDeadlineSeconds is the duration, measured in seconds since the
+	// job's StartTime, for which the job is allowed to keep running before
+	// the controller terminates it and marks it Failed with reason
+	// DeadlineExceeded. The zero value means no deadline.
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty"`
+
+	// BackoffLimit is the number of failed pods, counted across all replica
+	// types that use RestartPolicyOnFailure or RestartPolicyExitCode, that
+	// are tolerated before the job is marked Failed with reason
+	// BackoffLimitExceeded. The zero value means no limit.
+	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
+
+	// SchedulingPolicy configures gang scheduling for this TFJob. Only
+	// consulted when the controller is started with gang scheduling
+	// enabled; ignored otherwise.
+	SchedulingPolicy *SchedulingPolicy `json:"schedulingPolicy,omitempty"`
+
+	// TFReplicaSpecs is a map from TFReplicaType to the ReplicaSpec that
+	// describes the replicas of that type.
+	TFReplicaSpecs map[TFReplicaType]*TFReplicaSpec `json:"tfReplicaSpecs"`
+}
+
+// SchedulingPolicy configures gang scheduling for a TFJob: when the
+// controller is started with gang scheduling enabled, it creates a PodGroup
+// from these parameters before creating any of the TFJob's pods, so the
+// batch scheduler can place them all at once or not at all.
+type SchedulingPolicy struct {
+	// MinAvailable is the minimum number of pods that must be scheduled
+	// together before any of them are allowed to run. Defaults to the sum
+	// of every TFReplicaSpec's Replicas.
+	MinAvailable *int32 `json:"minAvailable,omitempty"`
+
+	// Queue is the name of the scheduling queue the TFJob's PodGroup is
+	// submitted to.
+	Queue string `json:"queue,omitempty"`
+
+	// PriorityClass is the name of the PriorityClass applied to the
+	// TFJob's PodGroup.
+	PriorityClass string `json:"priorityClass,omitempty"`
+
+	// SchedulerName is the name of the scheduler that should place the
+	// TFJob's pods. Stamped onto every pod template's spec.schedulerName.
+	SchedulerName string `json:"schedulerName,omitempty"`
+}
+
+// TFReplicaSpec is a description of a replica of a given type (e.g. worker
+// or parameter server) belonging to a TFJob.
+type TFReplicaSpec struct {
+	// Replicas is the number of desired replicas of this type. Defaults to 1.
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Template is the pod template used to create the replica's pod.
+	Template v1.PodTemplateSpec `json:"template,omitempty"`
+
+	// RestartPolicy determines whether pods of this replica type are
+	// restarted when they exit. Defaults to Never.
+	RestartPolicy RestartPolicy `json:"restartPolicy,omitempty"`
+
+	// FailurePolicy determines how the controller reacts when a pod of
+	// this replica type fails. When unset, a failed pod is handled by
+	// RestartPolicy alone and the other replicas' pods are left alone.
+	FailurePolicy FailurePolicy `json:"failurePolicy,omitempty"`
+}
+
+// TFJobConditionType defines all kinds of types of TFJobStatus.
+type TFJobConditionType string
+
+const (
+	// TFJobCreated means the TFJob has been accepted by the system.
+	TFJobCreated TFJobConditionType = "Created"
+	// TFJobRunning means all sub-resources (e.g. services/pods) of this TFJob
+	// have been successfully scheduled and at least one replica is running.
+	TFJobRunning TFJobConditionType = "Running"
+	// TFJobRestarting means one or more replicas of this TFJob have been
+	// restarted after a failure.
+	TFJobRestarting TFJobConditionType = "Restarting"
+	// TFJobSucceeded means all relevant replicas of this TFJob have
+	// completed successfully.
+	TFJobSucceeded TFJobConditionType = "Succeeded"
+	// TFJobFailed means one or more replicas of this TFJob have failed and
+	// the failure is not recoverable per the configured FailurePolicy, or
+	// the TFJob exceeded its ActiveDeadlineSeconds or BackoffLimit.
+	TFJobFailed TFJobConditionType = "Failed"
+	// TFJobSuspended means the TFJob's Spec.Suspend is true: its pods and
+	// services have been torn down but the TFJob itself still exists.
+	TFJobSuspended TFJobConditionType = "Suspended"
+)
+
+// TFJobCondition describes the state of a TFJob at a certain point.
+type TFJobCondition struct {
+	// Type of TFJob condition.
+	Type TFJobConditionType `json:"type"`
+	// Status of the condition, one of True, False, Unknown.
+	Status v1.ConditionStatus `json:"status"`
+	// Reason is a one-word, CamelCase reason for the condition's last
+	// transition.
+	Reason string `json:"reason,omitempty"`
+	// Message is a human-readable message indicating details about the
+	// last transition.
+	Message string `json:"message,omitempty"`
+	// LastUpdateTime is the time this condition was last updated.
+	LastUpdateTime metav1.Time `json:"lastUpdateTime,omitempty"`
+	// LastTransitionTime is the last time the condition transitioned from
+	// one status to another.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// TFReplicaStatus represents the current observed state of a TFReplicaSpec.
+type TFReplicaStatus struct {
+	// Active is the number of actively running pods.
+	Active int32 `json:"active,omitempty"`
+	// Succeeded is the number of pods that have completed successfully.
+	Succeeded int32 `json:"succeeded,omitempty"`
+	// Failed is the number of pods that have failed.
+	Failed int32 `json:"failed,omitempty"`
+}
+
+// TFJobStatus represents the current observed state of a TFJob.
+type TFJobStatus struct {
+	// Conditions is a list of the latest available observations of the
+	// TFJob's state.
+	Conditions []TFJobCondition `json:"conditions"`
+
+	// ReplicaStatuses is a map from TFReplicaType to the observed status of
+	// the replicas of that type.
+	ReplicaStatuses map[TFReplicaType]*TFReplicaStatus `json:"replicaStatuses"`
+
+	// StartTime is the time the TFJob was first considered active by the
+	// controller.
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is the time the TFJob reached a terminal condition.
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// LastReconcileTime is the time the controller last reconciled the
+	// TFJob.
+	LastReconcileTime *metav1.Time `json:"lastReconcileTime,omitempty"`
+}
@@ -0,0 +1,659 @@
+// Copyright 2018 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package controller provides a Kubernetes controller for a TFJob resource.
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang/glog"
+	"k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	kubeinformers "k8s.io/client-go/informers"
+	kubeclientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/kubernetes/pkg/controller"
+	vcclientset "volcano.sh/apis/pkg/client/clientset/versioned"
+
+	tfv1alpha2 "github.com/kubeflow/tf-operator/pkg/apis/tensorflow/v1alpha2"
+	tfjobclientset "github.com/kubeflow/tf-operator/pkg/client/clientset/versioned"
+)
+
+const (
+	controllerName = "tf-operator"
+)
+
+// controllerKind is the GroupVersionKind used to stamp owner references on
+// the pods and services created for a TFJob.
+var controllerKind = tfv1alpha2.SchemeGroupVersion.WithKind("TFJob")
+
+// TFController is the controller implementation for TFJob resources.
+type TFController struct {
+	kubeClientSet  kubeclientset.Interface
+	tfJobClientSet tfjobclientset.Interface
+
+	// tfJobInformer indexes TFJobs by namespace/name. Its entries are typed
+	// *tfv1alpha2.TFJob objects or *unstructured.Unstructured ones (as unit
+	// tests add them); tfJobFromObj accepts both.
+	tfJobInformer cache.SharedIndexInformer
+
+	podInformer     cache.SharedIndexInformer
+	serviceInformer cache.SharedIndexInformer
+
+	// *Synced default to the corresponding informer's HasSynced but are
+	// overridden in unit tests so syncing can be simulated without a real
+	// apiserver.
+	tfJobInformerSynced   cache.InformerSynced
+	podInformerSynced     cache.InformerSynced
+	serviceInformerSynced cache.InformerSynced
+
+	podControl      controller.PodControlInterface
+	serviceControl  ServiceControlInterface
+	podGroupControl PodGroupControlInterface
+
+	// workqueue is a rate limited work queue used to decouple the
+	// processing of TFJob changes from the speed at which they arrive.
+	workqueue workqueue.RateLimitingInterface
+
+	recorder record.EventRecorder
+
+	// syncHandler and updateStatusHandler are overridden in tests to
+	// observe/stub the controller's behavior without a real apiserver.
+	syncHandler         func(jobKey string) (bool, error)
+	updateStatusHandler func(tfJob *tfv1alpha2.TFJob) error
+
+	// enableGangScheduling controls whether the controller creates a
+	// PodGroup ahead of a TFJob's pods.
+	enableGangScheduling bool
+}
+
+// newTFJobController returns a new TFController along with the kube
+// informer factory and the TFJob informer it was built from, so that
+// callers (and tests) can start them and wait for their caches to sync.
+func newTFJobController(
+	config *rest.Config,
+	kubeClientSet kubeclientset.Interface,
+	tfJobClientSet tfjobclientset.Interface,
+	volcanoClientSet vcclientset.Interface,
+	enableGangScheduling bool,
+	resyncFunc func() time.Duration,
+) (*TFController, kubeinformers.SharedInformerFactory, cache.SharedIndexInformer) {
+
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubeClientSet, resyncFunc())
+	podInformer := kubeInformerFactory.Core().V1().Pods().Informer()
+	serviceInformer := kubeInformerFactory.Core().V1().Services().Informer()
+
+	tfJobInformer := newTFJobInformer(tfJobClientSet, resyncFunc())
+
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartLogging(glog.Infof)
+	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeClientSet.CoreV1().Events("")})
+	recorder := eventBroadcaster.NewRecorder(scheme.Scheme, v1.EventSource{Component: controllerName})
+
+	ctr := &TFController{
+		kubeClientSet:         kubeClientSet,
+		tfJobClientSet:        tfJobClientSet,
+		tfJobInformer:         tfJobInformer,
+		podInformer:           podInformer,
+		serviceInformer:       serviceInformer,
+		tfJobInformerSynced:   tfJobInformer.HasSynced,
+		podInformerSynced:     podInformer.HasSynced,
+		serviceInformerSynced: serviceInformer.HasSynced,
+		podControl:            controller.RealPodControl{KubeClient: kubeClientSet, Recorder: recorder},
+		serviceControl:        RealServiceControl{KubeClient: kubeClientSet, Recorder: recorder},
+		podGroupControl:       RealPodGroupControl{VolcanoClient: volcanoClientSet},
+		workqueue:             workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "TFJobs"),
+		recorder:              recorder,
+		enableGangScheduling:  enableGangScheduling,
+	}
+
+	ctr.syncHandler = ctr.syncTFJob
+	ctr.updateStatusHandler = ctr.updateTFJobStatusInCluster
+
+	podInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    ctr.addPod,
+		UpdateFunc: ctr.updatePod,
+		DeleteFunc: ctr.deletePod,
+	})
+
+	tfJobInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: ctr.enqueueTFJobForObj,
+		UpdateFunc: func(old, cur interface{}) {
+			ctr.enqueueTFJobForObj(cur)
+		},
+		DeleteFunc: ctr.enqueueTFJobForObj,
+	})
+
+	return ctr, kubeInformerFactory, tfJobInformer
+}
+
+// newTFJobInformer builds a SharedIndexInformer over typed TFJob objects,
+// listed and watched through the generated TFJob clientset, so the
+// controller does not need a generated informer/lister for the CRD.
+func newTFJobInformer(tfJobClientSet tfjobclientset.Interface, resync time.Duration) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return tfJobClientSet.TensorflowV1alpha2().TFJobs(metav1.NamespaceAll).List(options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return tfJobClientSet.TensorflowV1alpha2().TFJobs(metav1.NamespaceAll).Watch(options)
+			},
+		},
+		&tfv1alpha2.TFJob{},
+		resync,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+	)
+}
+
+// Run starts the controller: it waits for the informer caches to sync and
+// then starts threadiness workers processing items off the workqueue until
+// stopCh is closed.
+func (c *TFController) Run(threadiness int, stopCh <-chan struct{}) error {
+	defer utilruntime.HandleCrash()
+	defer c.workqueue.ShutDown()
+
+	glog.Info("Starting TFJob controller")
+
+	go c.tfJobInformer.Run(stopCh)
+
+	glog.Info("Waiting for informer caches to sync")
+	if ok := cache.WaitForCacheSync(stopCh, c.tfJobInformerSynced, c.podInformerSynced, c.serviceInformerSynced); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+
+	glog.Info("Starting workers")
+	for i := 0; i < threadiness; i++ {
+		go wait.Until(c.runWorker, time.Second, stopCh)
+	}
+
+	glog.Info("Started workers")
+	<-stopCh
+	glog.Info("Shutting down workers")
+
+	return nil
+}
+
+func (c *TFController) runWorker() {
+	for c.processNextWorkItem() {
+	}
+}
+
+func (c *TFController) processNextWorkItem() bool {
+	key, quit := c.workqueue.Get()
+	if quit {
+		return false
+	}
+	defer c.workqueue.Done(key)
+
+	forget, err := c.syncHandler(key.(string))
+	if err == nil {
+		if forget {
+			c.workqueue.Forget(key)
+		}
+		return true
+	}
+
+	utilruntime.HandleError(fmt.Errorf("error syncing TFJob %q: %v", key, err))
+	c.workqueue.AddRateLimited(key)
+	return true
+}
+
+// enqueueTFJob adds the TFJob identified by namespace/name to the workqueue.
+func (c *TFController) enqueueTFJob(key string) {
+	c.workqueue.Add(key)
+}
+
+// enqueueTFJobAfter adds the TFJob identified by namespace/name to the
+// workqueue after the given delay, e.g. so it gets re-checked once an
+// ActiveDeadlineSeconds timeout elapses.
+func (c *TFController) enqueueTFJobAfter(key string, after time.Duration) {
+	c.workqueue.AddAfter(key, after)
+}
+
+// enqueueTFJobForObj is the tfJobInformer's add/update/delete handler. obj is
+// either a typed *tfv1alpha2.TFJob or the *unstructured.Unstructured the
+// indexer stores. Registering it (rather than relying solely on pod events)
+// is what lets a brand-new TFJob with no pods yet - and, on resync, a
+// suspended TFJob with none recreated - get its key enqueued at all.
+func (c *TFController) enqueueTFJobForObj(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	key := getKeyForObj(obj)
+	if key == "" {
+		return
+	}
+	c.enqueueTFJob(key)
+}
+
+func (c *TFController) updateTFJobStatusInCluster(tfJob *tfv1alpha2.TFJob) error {
+	_, err := c.tfJobClientSet.TensorflowV1alpha2().TFJobs(tfJob.Namespace).UpdateStatus(tfJob)
+	return err
+}
+
+func (c *TFController) getTFJobFromName(namespace, name string) (*tfv1alpha2.TFJob, error) {
+	tfJob, err := c.tfJobClientSet.TensorflowV1alpha2().TFJobs(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return tfJob, nil
+}
+
+// asOwner returns an OwnerReference pointing at tfJob, used to stamp the
+// pods/services this controller creates on its behalf.
+func asOwner(tfJob *tfv1alpha2.TFJob) metav1.OwnerReference {
+	return *metav1.NewControllerRef(tfJob, controllerKind)
+}
+
+// asOwnerRef is asOwner, but returning a pointer as required by
+// controller.PodControlInterface.CreatePodsWithControllerRef.
+func asOwnerRef(tfJob *tfv1alpha2.TFJob) *metav1.OwnerReference {
+	ref := asOwner(tfJob)
+	return &ref
+}
+
+// syncTFJob is the controller's main reconcile function: given the
+// namespace/name key of a TFJob, it brings the cluster's state (pods,
+// services, TFJob status) in line with the job's spec. The returned bool
+// tells the caller whether the key can be forgotten by the workqueue's
+// rate limiter.
+func (c *TFController) syncTFJob(key string) (bool, error) {
+	namespace, name, err := splitKey(key)
+	if err != nil {
+		return false, err
+	}
+
+	obj, exists, err := c.tfJobInformer.GetIndexer().GetByKey(key)
+	if err != nil {
+		return false, err
+	}
+	if !exists {
+		glog.Infof("TFJob %s has been deleted", key)
+		return true, nil
+	}
+
+	sharedTFJob, err := tfJobFromObj(obj)
+	if err != nil {
+		return false, err
+	}
+	tfJob := sharedTFJob.DeepCopy()
+	tfJob.Namespace, tfJob.Name = namespace, name
+
+	if isSucceeded(tfJob) || isFailed(tfJob) {
+		return true, nil
+	}
+
+	if tfJob.Spec.Suspend != nil && *tfJob.Spec.Suspend {
+		return c.suspendTFJob(tfJob)
+	}
+	clearTFJobCondition(tfJob, tfv1alpha2.TFJobSuspended)
+
+	if tfJob.Status.StartTime == nil {
+		now := metav1.Now()
+		tfJob.Status.StartTime = &now
+	}
+	if tfJob.Spec.ActiveDeadlineSeconds != nil {
+		c.enqueueTFJobAfter(key, timeUntilActiveDeadline(tfJob))
+	}
+	if pastActiveDeadline(tfJob) {
+		return c.terminateTFJob(tfJob, tfv1alpha2.TFJobFailed, ReasonDeadlineExceeded,
+			"TFJob has exceeded its ActiveDeadlineSeconds", true)
+	}
+
+	pods, err := c.getPodsForTFJob(tfJob)
+	if err != nil {
+		return false, err
+	}
+	podsByType := groupPodsByReplicaType(pods)
+
+	if pastBackoffLimit(tfJob, podsByType) {
+		return c.terminateTFJob(tfJob, tfv1alpha2.TFJobFailed, ReasonBackoffLimitExceeded,
+			"TFJob has exceeded its BackoffLimit", false)
+	}
+
+	// tfReplicaTypes gives a fixed iteration order over the
+	// TFReplicaSpecs map so which FailurePolicy applies does not depend on
+	// Go's randomized map order. Fail is also checked in its own pass ahead
+	// of RestartAll so it always wins precedence, regardless of which
+	// replica's failure is observed first: otherwise a RestartAll replica
+	// could delete every pod - including the one that should have failed
+	// the job - before the Fail case is ever evaluated.
+	for _, rtype := range tfReplicaTypes {
+		spec, ok := tfJob.Spec.TFReplicaSpecs[rtype]
+		if !ok {
+			continue
+		}
+		for _, pod := range podsByType[rtype] {
+			if pod.Status.Phase == v1.PodFailed && effectiveFailurePolicy(spec) == tfv1alpha2.FailurePolicyFail {
+				return c.terminateTFJob(tfJob, tfv1alpha2.TFJobFailed, "FailurePolicyFail",
+					fmt.Sprintf("replica %s failed and FailurePolicy is Fail", tfReplicaTypeLower(rtype)), false)
+			}
+		}
+	}
+
+	restartAll := false
+findFailurePolicy:
+	for _, rtype := range tfReplicaTypes {
+		spec, ok := tfJob.Spec.TFReplicaSpecs[rtype]
+		if !ok {
+			continue
+		}
+		for _, pod := range podsByType[rtype] {
+			if pod.Status.Phase == v1.PodFailed && effectiveFailurePolicy(spec) == tfv1alpha2.FailurePolicyRestartAll {
+				restartAll = true
+				break findFailurePolicy
+			}
+		}
+	}
+	if restartAll {
+		if err := c.restartAllPods(tfJob, pods); err != nil {
+			return false, err
+		}
+		// Every pod has just been deleted; the reconcile loop below will
+		// see none left and recreate them all from scratch.
+		podsByType = map[tfv1alpha2.TFReplicaType][]*v1.Pod{}
+	}
+
+	if c.enableGangScheduling {
+		podGroup, err := c.syncPodGroup(tfJob)
+		if err != nil {
+			return false, err
+		}
+		if !podGroupReady(podGroup) {
+			// Not enough resources admitted yet: leave every replica type's
+			// pods uncreated and recheck on the next resync.
+			updateTFJobConditions(tfJob, tfv1alpha2.TFJobCreated, "PodGroupPending",
+				"Waiting for the PodGroup to be admitted by the scheduler")
+			if err := c.updateStatusHandler(tfJob); err != nil {
+				return false, err
+			}
+			return true, nil
+		}
+	}
+
+	for rtype, spec := range tfJob.Spec.TFReplicaSpecs {
+		rt := tfReplicaTypeLower(rtype)
+		replicas := int32(1)
+		if spec.Replicas != nil {
+			replicas = *spec.Replicas
+		}
+		for index := int32(0); index < replicas; index++ {
+			if err := c.createNewService(tfJob, rt, fmt.Sprintf("%d", index)); err != nil && !apierrors.IsAlreadyExists(err) {
+				// Service creation is best-effort: a replica's pod can
+				// still make progress, and TF_CONFIG only needs the
+				// service to exist by the time the pod resolves its
+				// peers' addresses, not by the time it is scheduled. Log
+				// and let the next resync retry it.
+				glog.Warningf("Failed to create service %s-%s-%d for TFJob %s: %v", tfJob.Name, rt, index, key, err)
+			}
+		}
+		if err := c.reconcilePods(tfJob, rtype, spec, podsByType[rtype]); err != nil {
+			return false, err
+		}
+		updateTFJobReplicaStatuses(tfJob, rtype, podsByType[rtype])
+	}
+
+	if isJobSucceeded(tfJob, podsByType) {
+		now := metav1.Now()
+		tfJob.Status.CompletionTime = &now
+		updateTFJobConditions(tfJob, tfv1alpha2.TFJobSucceeded, "TFJobSucceeded", "TFJob has successfully completed")
+		services, err := c.getServicesForTFJob(tfJob)
+		if err != nil {
+			return false, err
+		}
+		if err := c.cleanupTFJob(tfJob, pods, services, false); err != nil {
+			return false, err
+		}
+	} else {
+		updateTFJobConditions(tfJob, tfv1alpha2.TFJobRunning, "TFJobRunning", "TFJob is running")
+	}
+
+	if err := c.updateStatusHandler(tfJob); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// terminateTFJob marks tfJob with a terminal condition, deletes its owned
+// pods/services per CleanPodPolicy (unless forceDeletePods is set, in
+// which case pods are deleted regardless of CleanPodPolicy), and persists
+// the status update.
+func (c *TFController) terminateTFJob(tfJob *tfv1alpha2.TFJob, condType tfv1alpha2.TFJobConditionType, reason, message string, forceDeletePods bool) (bool, error) {
+	pods, err := c.getPodsForTFJob(tfJob)
+	if err != nil {
+		return false, err
+	}
+	services, err := c.getServicesForTFJob(tfJob)
+	if err != nil {
+		return false, err
+	}
+	if err := c.cleanupTFJob(tfJob, pods, services, forceDeletePods); err != nil {
+		return false, err
+	}
+
+	now := metav1.Now()
+	tfJob.Status.CompletionTime = &now
+	updateTFJobConditions(tfJob, condType, reason, message)
+	if err := c.updateStatusHandler(tfJob); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// effectiveFailurePolicy returns spec.FailurePolicy unchanged. When unset,
+// it is neither Fail nor RestartAll nor Ignore, so a failed pod falls
+// through to handleFailedPod's per-pod RestartPolicy handling instead of
+// tearing down the whole job.
+func effectiveFailurePolicy(spec *tfv1alpha2.TFReplicaSpec) tfv1alpha2.FailurePolicy {
+	return spec.FailurePolicy
+}
+
+// restartAllPods deletes every pod owned by tfJob, used to implement
+// FailurePolicyRestartAll: once any replica fails, the whole distributed
+// training run is torn down and recreated from scratch.
+func (c *TFController) restartAllPods(tfJob *tfv1alpha2.TFJob, pods []*v1.Pod) error {
+	for _, pod := range pods {
+		if err := c.podControl.DeletePod(pod.Namespace, pod.Name, tfJob); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// suspendTFJob tears down tfJob's owned pods/services (per CleanPodPolicy)
+// and clears its StartTime, without deleting the TFJob itself. It is
+// idempotent: a job that is already suspended with no pods left just
+// refreshes its Suspended condition.
+func (c *TFController) suspendTFJob(tfJob *tfv1alpha2.TFJob) (bool, error) {
+	pods, err := c.getPodsForTFJob(tfJob)
+	if err != nil {
+		return false, err
+	}
+	services, err := c.getServicesForTFJob(tfJob)
+	if err != nil {
+		return false, err
+	}
+	if len(pods) > 0 || len(services) > 0 {
+		if err := c.cleanupTFJob(tfJob, pods, services, false); err != nil {
+			return false, err
+		}
+	}
+
+	tfJob.Status.StartTime = nil
+	updateTFJobConditions(tfJob, tfv1alpha2.TFJobSuspended, "TFJobSuspended", "TFJob is suspended")
+	if err := c.updateStatusHandler(tfJob); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// isJobSucceeded reports whether tfJob has completed, per its
+// SuccessPolicy. SuccessPolicyAllWorkers requires every Worker replica to
+// have succeeded; the Default policy is the chief/master replica
+// succeeding or, for a chiefless job (no Chief or Master replica, see
+// tfv1alpha2.IsChieflessJob), worker:0 succeeding.
+func isJobSucceeded(tfJob *tfv1alpha2.TFJob, podsByType map[tfv1alpha2.TFReplicaType][]*v1.Pod) bool {
+	if tfJob.Spec.SuccessPolicy != nil && *tfJob.Spec.SuccessPolicy == tfv1alpha2.SuccessPolicyAllWorkers {
+		return allWorkersSucceeded(tfJob, podsByType)
+	}
+
+	if tfv1alpha2.IsChieflessJob(tfJob) {
+		return worker0Succeeded(podsByType)
+	}
+
+	for _, chiefType := range []tfv1alpha2.TFReplicaType{tfv1alpha2.TFReplicaTypeChief, tfv1alpha2.TFReplicaTypeMaster} {
+		if _, ok := tfJob.Spec.TFReplicaSpecs[chiefType]; !ok {
+			continue
+		}
+		for _, pod := range podsByType[chiefType] {
+			if pod.Status.Phase == v1.PodSucceeded {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Unreachable: IsChieflessJob already returned false above, which
+	// guarantees a Chief or Master replica is configured, so the loop
+	// above always returns.
+	return false
+}
+
+// worker0Succeeded reports whether the worker:0 pod has reached
+// PodSucceeded. Used to determine completion of chiefless jobs, where no
+// other replica's success implies the whole job is done.
+func worker0Succeeded(podsByType map[tfv1alpha2.TFReplicaType][]*v1.Pod) bool {
+	for _, pod := range podsByType[tfv1alpha2.TFReplicaTypeWorker] {
+		if pod.Labels[tfReplicaIndexLabel] == "0" && pod.Status.Phase == v1.PodSucceeded {
+			return true
+		}
+	}
+	return false
+}
+
+// allWorkersSucceeded reports whether every expected Worker replica has a
+// pod in PodSucceeded.
+func allWorkersSucceeded(tfJob *tfv1alpha2.TFJob, podsByType map[tfv1alpha2.TFReplicaType][]*v1.Pod) bool {
+	workers := podsByType[tfv1alpha2.TFReplicaTypeWorker]
+	if len(workers) == 0 {
+		return false
+	}
+
+	replicas := int32(1)
+	if spec, ok := tfJob.Spec.TFReplicaSpecs[tfv1alpha2.TFReplicaTypeWorker]; ok && spec.Replicas != nil {
+		replicas = *spec.Replicas
+	}
+	if int32(len(workers)) < replicas {
+		return false
+	}
+
+	for _, pod := range workers {
+		if pod.Status.Phase != v1.PodSucceeded {
+			return false
+		}
+	}
+	return true
+}
+
+// resolveControllerRef looks up the TFJob named by controllerRef in
+// namespace, returning nil if it no longer exists or the reference does
+// not actually point at a TFJob.
+func (c *TFController) resolveControllerRef(namespace string, controllerRef *metav1.OwnerReference) *tfv1alpha2.TFJob {
+	if controllerRef.Kind != controllerKind.Kind {
+		return nil
+	}
+	obj, exists, err := c.tfJobInformer.GetIndexer().GetByKey(namespace + "/" + controllerRef.Name)
+	if err != nil || !exists {
+		return nil
+	}
+	tfJob, err := tfJobFromObj(obj)
+	if err != nil {
+		return nil
+	}
+	if tfJob.UID != controllerRef.UID {
+		return nil
+	}
+	return tfJob
+}
+
+func (c *TFController) addPod(obj interface{}) {
+	pod, ok := obj.(*v1.Pod)
+	if !ok {
+		return
+	}
+	if pod.DeletionTimestamp != nil {
+		c.deletePod(pod)
+		return
+	}
+	controllerRef := metav1.GetControllerOf(pod)
+	if controllerRef == nil {
+		return
+	}
+	tfJob := c.resolveControllerRef(pod.Namespace, controllerRef)
+	if tfJob == nil {
+		return
+	}
+	c.enqueueTFJob(getKeyForObj(tfJob))
+}
+
+func (c *TFController) updatePod(old, cur interface{}) {
+	curPod, ok := cur.(*v1.Pod)
+	if !ok {
+		return
+	}
+	oldPod, ok := old.(*v1.Pod)
+	if ok && curPod.ResourceVersion == oldPod.ResourceVersion {
+		return
+	}
+	c.addPod(curPod)
+}
+
+func (c *TFController) deletePod(obj interface{}) {
+	pod, ok := obj.(*v1.Pod)
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			return
+		}
+		pod, ok = tombstone.Obj.(*v1.Pod)
+		if !ok {
+			return
+		}
+	}
+	controllerRef := metav1.GetControllerOf(pod)
+	if controllerRef == nil {
+		return
+	}
+	tfJob := c.resolveControllerRef(pod.Namespace, controllerRef)
+	if tfJob == nil {
+		return
+	}
+	c.enqueueTFJob(getKeyForObj(tfJob))
+}
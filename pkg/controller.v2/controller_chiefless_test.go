@@ -0,0 +1,57 @@
+// Copyright 2018 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"testing"
+
+	"k8s.io/api/core/v1"
+
+	tfv1alpha2 "github.com/kubeflow/tf-operator/pkg/apis/tensorflow/v1alpha2"
+)
+
+// TestChieflessJobSucceedsOnWorker0 verifies that a TFJob with only Worker
+// replicas (no Chief/Master) is marked Succeeded as soon as worker:0
+// finishes, even while other workers are still running.
+func TestChieflessJobSucceedsOnWorker0(t *testing.T) {
+	ctr, _ := newTestController()
+
+	tfJob := newTFJob(2, 0)
+	if !tfv1alpha2.IsChieflessJob(tfJob) {
+		t.Fatalf("expected newTFJob(2, 0) to be chiefless")
+	}
+
+	var updated *tfv1alpha2.TFJob
+	ctr.updateStatusHandler = func(job *tfv1alpha2.TFJob) error {
+		updated = job
+		return nil
+	}
+	setupTFJob(t, ctr, tfJob)
+
+	podIndexer := ctr.podInformer.GetIndexer()
+	worker0 := newPod(tfJob, labelWorker, 0, t)
+	worker0.Status.Phase = v1.PodSucceeded
+	podIndexer.Add(worker0)
+	worker1 := newPod(tfJob, labelWorker, 1, t)
+	worker1.Status.Phase = v1.PodRunning
+	podIndexer.Add(worker1)
+
+	if _, err := ctr.syncTFJob(getKey(tfJob, t)); err != nil {
+		t.Fatalf("unexpected error when syncing job: %v", err)
+	}
+	if updated == nil || !isSucceeded(updated) {
+		t.Errorf("Expected chiefless TFJob to be Succeeded once worker:0 succeeds, even with worker:1 still running")
+	}
+}
@@ -0,0 +1,231 @@
+// Copyright 2018 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeclientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/kubernetes/pkg/controller"
+	vcclientset "volcano.sh/apis/pkg/client/clientset/versioned"
+
+	tfv1alpha2 "github.com/kubeflow/tf-operator/pkg/apis/tensorflow/v1alpha2"
+	tfjobclientset "github.com/kubeflow/tf-operator/pkg/client/clientset/versioned"
+)
+
+func newTestController() (*TFController, *controller.FakePodControl) {
+	kubeClientSet := kubeclientset.NewForConfigOrDie(&rest.Config{
+		Host: "",
+		ContentConfig: rest.ContentConfig{
+			GroupVersion: &v1.SchemeGroupVersion,
+		},
+	})
+	config := &rest.Config{
+		Host: "",
+		ContentConfig: rest.ContentConfig{
+			GroupVersion: &tfv1alpha2.SchemeGroupVersion,
+		},
+	}
+	tfJobClientSet := tfjobclientset.NewForConfigOrDie(config)
+	volcanoClientSet := vcclientset.NewForConfigOrDie(config)
+	ctr, _, _ := newTFJobController(config, kubeClientSet, tfJobClientSet, volcanoClientSet, false, controller.NoResyncPeriodFunc)
+	fakePodControl := &controller.FakePodControl{}
+	ctr.podControl = fakePodControl
+	ctr.tfJobInformerSynced = alwaysReady
+	ctr.podInformerSynced = alwaysReady
+	ctr.serviceInformerSynced = alwaysReady
+	return ctr, fakePodControl
+}
+
+func TestActiveDeadlineSeconds(t *testing.T) {
+	ctr, fakePodControl := newTestController()
+
+	tfJob := newTFJob(1, 0)
+	deadline := int64(10)
+	tfJob.Spec.ActiveDeadlineSeconds = &deadline
+	startTime := metav1.NewTime(time.Now().Add(-1 * time.Hour))
+	tfJob.Status.StartTime = &startTime
+
+	var updated *tfv1alpha2.TFJob
+	ctr.updateStatusHandler = func(job *tfv1alpha2.TFJob) error {
+		updated = job
+		return nil
+	}
+
+	tfJobIndexer := ctr.tfJobInformer.GetIndexer()
+	unstructured, err := convertTFJobToUnstructured(tfJob)
+	if err != nil {
+		t.Fatalf("Failed to convert the TFJob to Unstructured: %v", err)
+	}
+	if err := tfJobIndexer.Add(unstructured); err != nil {
+		t.Fatalf("Failed to add tfjob to tfJobIndexer: %v", err)
+	}
+
+	pod := newPod(tfJob, labelWorker, 0, t)
+	pod.Status.Phase = v1.PodRunning
+	ctr.podInformer.GetIndexer().Add(pod)
+
+	if _, err := ctr.syncTFJob(getKey(tfJob, t)); err != nil {
+		t.Errorf("unexpected error when syncing job: %v", err)
+	}
+
+	found := false
+	for _, name := range fakePodControl.DeletePodName {
+		if name == pod.Name {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected pod %s to be deleted once past ActiveDeadlineSeconds", pod.Name)
+	}
+	if updated == nil || !isFailed(updated) {
+		t.Errorf("Expected TFJob to be marked Failed")
+	}
+	if !hasConditionReason(updated, tfv1alpha2.TFJobFailed, ReasonDeadlineExceeded) {
+		t.Errorf("Expected Failed condition with reason %s", ReasonDeadlineExceeded)
+	}
+}
+
+func TestActiveDeadlineSecondsIgnoresCleanPodPolicyNone(t *testing.T) {
+	ctr, fakePodControl := newTestController()
+
+	tfJob := newTFJob(1, 0)
+	deadline := int64(10)
+	tfJob.Spec.ActiveDeadlineSeconds = &deadline
+	cleanNone := tfv1alpha2.CleanPodPolicyNone
+	tfJob.Spec.CleanPodPolicy = &cleanNone
+	startTime := metav1.NewTime(time.Now().Add(-1 * time.Hour))
+	tfJob.Status.StartTime = &startTime
+
+	tfJobIndexer := ctr.tfJobInformer.GetIndexer()
+	unstructured, err := convertTFJobToUnstructured(tfJob)
+	if err != nil {
+		t.Fatalf("Failed to convert the TFJob to Unstructured: %v", err)
+	}
+	if err := tfJobIndexer.Add(unstructured); err != nil {
+		t.Fatalf("Failed to add tfjob to tfJobIndexer: %v", err)
+	}
+
+	pod := newPod(tfJob, labelWorker, 0, t)
+	pod.Status.Phase = v1.PodRunning
+	ctr.podInformer.GetIndexer().Add(pod)
+
+	if _, err := ctr.syncTFJob(getKey(tfJob, t)); err != nil {
+		t.Errorf("unexpected error when syncing job: %v", err)
+	}
+
+	found := false
+	for _, name := range fakePodControl.DeletePodName {
+		if name == pod.Name {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected pod %s to be deleted once past ActiveDeadlineSeconds, even with CleanPodPolicy=None", pod.Name)
+	}
+}
+
+func TestBackoffLimit(t *testing.T) {
+	ctr, fakePodControl := newTestController()
+
+	tfJob := newTFJob(1, 0)
+	backoffLimit := int32(1)
+	tfJob.Spec.BackoffLimit = &backoffLimit
+	cleanAll := tfv1alpha2.CleanPodPolicyAll
+	tfJob.Spec.CleanPodPolicy = &cleanAll
+	tfJob.Spec.TFReplicaSpecs[tfv1alpha2.TFReplicaTypeWorker].Replicas = int32Ptr(2)
+	tfJob.Spec.TFReplicaSpecs[tfv1alpha2.TFReplicaTypeWorker].RestartPolicy = tfv1alpha2.RestartPolicyOnFailure
+
+	var updated *tfv1alpha2.TFJob
+	ctr.updateStatusHandler = func(job *tfv1alpha2.TFJob) error {
+		updated = job
+		return nil
+	}
+
+	tfJobIndexer := ctr.tfJobInformer.GetIndexer()
+	unstructured, err := convertTFJobToUnstructured(tfJob)
+	if err != nil {
+		t.Fatalf("Failed to convert the TFJob to Unstructured: %v", err)
+	}
+	if err := tfJobIndexer.Add(unstructured); err != nil {
+		t.Fatalf("Failed to add tfjob to tfJobIndexer: %v", err)
+	}
+
+	podIndexer := ctr.podInformer.GetIndexer()
+	for _, pod := range newPodList(2, v1.PodFailed, tfJob, labelWorker, 0, t) {
+		podIndexer.Add(pod)
+	}
+
+	if _, err := ctr.syncTFJob(getKey(tfJob, t)); err != nil {
+		t.Errorf("unexpected error when syncing job: %v", err)
+	}
+
+	if len(fakePodControl.DeletePodName) == 0 {
+		t.Errorf("Expected failed pods to be deleted once past BackoffLimit")
+	}
+	if updated == nil || !isFailed(updated) {
+		t.Errorf("Expected TFJob to be marked Failed")
+	}
+	if !hasConditionReason(updated, tfv1alpha2.TFJobFailed, ReasonBackoffLimitExceeded) {
+		t.Errorf("Expected Failed condition with reason %s", ReasonBackoffLimitExceeded)
+	}
+}
+
+func TestBackoffLimitHonorsCleanPodPolicyNone(t *testing.T) {
+	ctr, fakePodControl := newTestController()
+
+	tfJob := newTFJob(1, 0)
+	backoffLimit := int32(1)
+	tfJob.Spec.BackoffLimit = &backoffLimit
+	cleanNone := tfv1alpha2.CleanPodPolicyNone
+	tfJob.Spec.CleanPodPolicy = &cleanNone
+	tfJob.Spec.TFReplicaSpecs[tfv1alpha2.TFReplicaTypeWorker].Replicas = int32Ptr(2)
+	tfJob.Spec.TFReplicaSpecs[tfv1alpha2.TFReplicaTypeWorker].RestartPolicy = tfv1alpha2.RestartPolicyOnFailure
+
+	tfJobIndexer := ctr.tfJobInformer.GetIndexer()
+	unstructured, err := convertTFJobToUnstructured(tfJob)
+	if err != nil {
+		t.Fatalf("Failed to convert the TFJob to Unstructured: %v", err)
+	}
+	if err := tfJobIndexer.Add(unstructured); err != nil {
+		t.Fatalf("Failed to add tfjob to tfJobIndexer: %v", err)
+	}
+
+	podIndexer := ctr.podInformer.GetIndexer()
+	for _, pod := range newPodList(2, v1.PodFailed, tfJob, labelWorker, 0, t) {
+		podIndexer.Add(pod)
+	}
+
+	if _, err := ctr.syncTFJob(getKey(tfJob, t)); err != nil {
+		t.Errorf("unexpected error when syncing job: %v", err)
+	}
+
+	if len(fakePodControl.DeletePodName) != 0 {
+		t.Errorf("Expected BackoffLimitExceeded to honor CleanPodPolicy=None and leave pods in place, got deletions: %v", fakePodControl.DeletePodName)
+	}
+}
+
+func hasConditionReason(tfJob *tfv1alpha2.TFJob, condType tfv1alpha2.TFJobConditionType, reason string) bool {
+	for _, c := range tfJob.Status.Conditions {
+		if c.Type == condType && c.Reason == reason {
+			return true
+		}
+	}
+	return false
+}
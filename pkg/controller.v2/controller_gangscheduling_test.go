@@ -0,0 +1,141 @@
+// Copyright 2018 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	volcanov1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+
+	tfv1alpha2 "github.com/kubeflow/tf-operator/pkg/apis/tensorflow/v1alpha2"
+)
+
+// FakePodGroupControl is an in-memory PodGroupControlInterface used by
+// tests, mirroring k8s.io/kubernetes/pkg/controller.FakePodControl.
+type FakePodGroupControl struct {
+	PodGroups []*volcanov1beta1.PodGroup
+}
+
+func (f *FakePodGroupControl) GetPodGroup(namespace, name string) (*volcanov1beta1.PodGroup, error) {
+	for _, pg := range f.PodGroups {
+		if pg.Namespace == namespace && pg.Name == name {
+			return pg, nil
+		}
+	}
+	return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "podgroups"}, name)
+}
+
+func (f *FakePodGroupControl) CreatePodGroup(podGroup *volcanov1beta1.PodGroup) (*volcanov1beta1.PodGroup, error) {
+	f.PodGroups = append(f.PodGroups, podGroup)
+	return podGroup, nil
+}
+
+func (f *FakePodGroupControl) UpdatePodGroup(podGroup *volcanov1beta1.PodGroup) (*volcanov1beta1.PodGroup, error) {
+	for i, pg := range f.PodGroups {
+		if pg.Namespace == podGroup.Namespace && pg.Name == podGroup.Name {
+			f.PodGroups[i] = podGroup
+			return podGroup, nil
+		}
+	}
+	return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "podgroups"}, podGroup.Name)
+}
+
+func TestGangSchedulingCreatesPodGroupAndLabelsPods(t *testing.T) {
+	ctr, fakePodControl := newTestController()
+	fakePodGroupControl := &FakePodGroupControl{}
+	ctr.podGroupControl = fakePodGroupControl
+	ctr.enableGangScheduling = true
+
+	tfJob := newTFJob(2, 1)
+	schedulerName := "volcano"
+	tfJob.Spec.SchedulingPolicy = &tfv1alpha2.SchedulingPolicy{SchedulerName: schedulerName}
+	setupTFJob(t, ctr, tfJob)
+
+	if _, err := ctr.syncTFJob(getKey(tfJob, t)); err != nil {
+		t.Fatalf("unexpected error when syncing job: %v", err)
+	}
+
+	if len(fakePodGroupControl.PodGroups) != 1 {
+		t.Fatalf("Expected exactly one PodGroup to be created, got %d", len(fakePodGroupControl.PodGroups))
+	}
+	podGroup := fakePodGroupControl.PodGroups[0]
+	if podGroup.Spec.MinMember != totalReplicas(tfJob) {
+		t.Errorf("Expected MinMember %d, got %d", totalReplicas(tfJob), podGroup.Spec.MinMember)
+	}
+	if podGroup.Labels[tfJobNameLabel] != tfJob.Name {
+		t.Errorf("Expected PodGroup to carry the TFJob's labels, got %v", podGroup.Labels)
+	}
+
+	// With no PodGroup status reported yet (PodGroupPending), pod creation
+	// must be blocked.
+	if len(fakePodControl.Templates) != 0 {
+		t.Errorf("Expected no pods to be created before the PodGroup is admitted, got %d", len(fakePodControl.Templates))
+	}
+
+	// Once the scheduler admits the PodGroup, pods are created and stamped
+	// with the group-name annotation and the configured SchedulerName.
+	podGroup.Status.Phase = volcanov1beta1.PodGroupInqueue
+	if _, err := ctr.syncTFJob(getKey(tfJob, t)); err != nil {
+		t.Fatalf("unexpected error when syncing job: %v", err)
+	}
+	if len(fakePodControl.Templates) == 0 {
+		t.Fatalf("Expected pods to be created once the PodGroup is admitted")
+	}
+	for _, tmpl := range fakePodControl.Templates {
+		if tmpl.Annotations[groupNameAnnotation] != podGroupName(tfJob) {
+			t.Errorf("Expected pod annotation %s=%s, got %v", groupNameAnnotation, podGroupName(tfJob), tmpl.Annotations)
+		}
+		if tmpl.Spec.SchedulerName != schedulerName {
+			t.Errorf("Expected pod schedulerName %s, got %s", schedulerName, tmpl.Spec.SchedulerName)
+		}
+	}
+}
+
+func TestGangSchedulingReconcilesExistingPodGroupSpec(t *testing.T) {
+	ctr, _ := newTestController()
+	fakePodGroupControl := &FakePodGroupControl{}
+	ctr.podGroupControl = fakePodGroupControl
+	ctr.enableGangScheduling = true
+
+	tfJob := newTFJob(2, 1)
+	tfJob.Spec.SchedulingPolicy = &tfv1alpha2.SchedulingPolicy{Queue: "default"}
+	setupTFJob(t, ctr, tfJob)
+
+	if _, err := ctr.syncTFJob(getKey(tfJob, t)); err != nil {
+		t.Fatalf("unexpected error when syncing job: %v", err)
+	}
+	if len(fakePodGroupControl.PodGroups) != 1 {
+		t.Fatalf("Expected exactly one PodGroup to be created, got %d", len(fakePodGroupControl.PodGroups))
+	}
+	if got := fakePodGroupControl.PodGroups[0].Spec.Queue; got != "default" {
+		t.Fatalf("Expected PodGroup Queue %q, got %q", "default", got)
+	}
+
+	// Changing the TFJob's SchedulingPolicy after the PodGroup already
+	// exists must be reflected onto the PodGroup rather than ignored.
+	tfJob.Spec.SchedulingPolicy.Queue = "high-priority"
+	setupTFJob(t, ctr, tfJob)
+	if _, err := ctr.syncTFJob(getKey(tfJob, t)); err != nil {
+		t.Fatalf("unexpected error when syncing job: %v", err)
+	}
+	if len(fakePodGroupControl.PodGroups) != 1 {
+		t.Fatalf("Expected the PodGroup to be updated in place, got %d PodGroups", len(fakePodGroupControl.PodGroups))
+	}
+	if got := fakePodGroupControl.PodGroups[0].Spec.Queue; got != "high-priority" {
+		t.Errorf("Expected PodGroup Queue to be updated to %q, got %q", "high-priority", got)
+	}
+}
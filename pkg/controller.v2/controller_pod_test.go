@@ -25,6 +25,7 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/kubernetes/pkg/controller"
+	vcclientset "volcano.sh/apis/pkg/client/clientset/versioned"
 
 	tfv1alpha2 "github.com/kubeflow/tf-operator/pkg/apis/tensorflow/v1alpha2"
 	tfjobclientset "github.com/kubeflow/tf-operator/pkg/client/clientset/versioned"
@@ -94,7 +95,8 @@ func TestAddPod(t *testing.T) {
 		},
 	}
 	tfJobClientSet := tfjobclientset.NewForConfigOrDie(config)
-	ctr, _, _ := newTFJobController(config, kubeClientSet, tfJobClientSet, controller.NoResyncPeriodFunc)
+	volcanoClientSet := vcclientset.NewForConfigOrDie(config)
+	ctr, _, _ := newTFJobController(config, kubeClientSet, tfJobClientSet, volcanoClientSet, false, controller.NoResyncPeriodFunc)
 	ctr.tfJobInformerSynced = alwaysReady
 	ctr.podInformerSynced = alwaysReady
 	ctr.serviceInformerSynced = alwaysReady
@@ -156,6 +158,17 @@ func TestClusterSpec(t *testing.T) {
 				`-ps-0.default.svc.cluster.local:2222"],"worker":["` + testTFJobName +
 				`-worker-0.default.svc.cluster.local:2222"]},"task":{"type":"worker","index":0}}`,
 		},
+		tc{
+			// A chiefless job (no Chief/Master replica) must not grow a
+			// synthetic chief entry in the cluster spec: every worker,
+			// including worker:0, keeps task type "worker".
+			tfJob: newTFJob(2, 0),
+			rt:    "worker",
+			index: "1",
+			expectedClusterSpec: `{"cluster":{"worker":["` + testTFJobName +
+				`-worker-0.default.svc.cluster.local:2222","` + testTFJobName +
+				`-worker-1.default.svc.cluster.local:2222"]},"task":{"type":"worker","index":1}}`,
+		},
 	}
 	for _, c := range testCase {
 		demoTemplateSpec := c.tfJob.Spec.TFReplicaSpecs[tfv1alpha2.TFReplicaTypeWorker].Template
@@ -253,7 +266,8 @@ func TestExitCode(t *testing.T) {
 		},
 	}
 	tfJobClientSet := tfjobclientset.NewForConfigOrDie(config)
-	ctr, kubeInformerFactory, _ := newTFJobController(config, kubeClientSet, tfJobClientSet, controller.NoResyncPeriodFunc)
+	volcanoClientSet := vcclientset.NewForConfigOrDie(config)
+	ctr, kubeInformerFactory, _ := newTFJobController(config, kubeClientSet, tfJobClientSet, volcanoClientSet, false, controller.NoResyncPeriodFunc)
 	fakePodControl := &controller.FakePodControl{}
 	ctr.podControl = fakePodControl
 	ctr.tfJobInformerSynced = alwaysReady
@@ -311,3 +325,34 @@ func TestExitCode(t *testing.T) {
 	}
 	close(stopCh)
 }
+
+// TestExitCodeNonTransient verifies that, unlike the transient exit codes
+// covered by TestExitCode, a non-transient exit code (e.g. an application
+// crash) leaves the failed pod in place instead of restarting it.
+func TestExitCodeNonTransient(t *testing.T) {
+	ctr, fakePodControl := newTestController()
+
+	tfJob := newTFJob(1, 0)
+	tfJob.Spec.TFReplicaSpecs[tfv1alpha2.TFReplicaTypeWorker].RestartPolicy = tfv1alpha2.RestartPolicyExitCode
+	setupTFJob(t, ctr, tfJob)
+
+	pod := newPod(tfJob, labelWorker, 0, t)
+	pod.Status.Phase = v1.PodFailed
+	pod.Spec.Containers = append(pod.Spec.Containers, v1.Container{})
+	pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, v1.ContainerStatus{
+		Name: tfv1alpha2.DefaultContainerName,
+		State: v1.ContainerState{
+			Terminated: &v1.ContainerStateTerminated{
+				ExitCode: 1,
+			},
+		},
+	})
+	ctr.podInformer.GetIndexer().Add(pod)
+
+	if _, err := ctr.syncTFJob(getKey(tfJob, t)); err != nil {
+		t.Fatalf("unexpected error when syncing job: %v", err)
+	}
+	if len(fakePodControl.DeletePodName) != 0 {
+		t.Errorf("Expected a non-transient exit code to leave the failed pod alone, got deletions: %v", fakePodControl.DeletePodName)
+	}
+}
@@ -0,0 +1,255 @@
+// Copyright 2018 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"testing"
+
+	"k8s.io/api/core/v1"
+
+	tfv1alpha2 "github.com/kubeflow/tf-operator/pkg/apis/tensorflow/v1alpha2"
+)
+
+func setupTFJob(t *testing.T, ctr *TFController, tfJob *tfv1alpha2.TFJob) {
+	unstructured, err := convertTFJobToUnstructured(tfJob)
+	if err != nil {
+		t.Fatalf("Failed to convert the TFJob to Unstructured: %v", err)
+	}
+	if err := ctr.tfJobInformer.GetIndexer().Add(unstructured); err != nil {
+		t.Fatalf("Failed to add tfjob to tfJobIndexer: %v", err)
+	}
+}
+
+func TestSuccessPolicyAllWorkers(t *testing.T) {
+	ctr, _ := newTestController()
+
+	tfJob := newTFJob(2, 0)
+	successPolicy := tfv1alpha2.SuccessPolicyAllWorkers
+	tfJob.Spec.SuccessPolicy = &successPolicy
+
+	var updated *tfv1alpha2.TFJob
+	ctr.updateStatusHandler = func(job *tfv1alpha2.TFJob) error {
+		updated = job
+		return nil
+	}
+	setupTFJob(t, ctr, tfJob)
+
+	podIndexer := ctr.podInformer.GetIndexer()
+	for _, pod := range newPodList(1, v1.PodSucceeded, tfJob, labelWorker, 0, t) {
+		podIndexer.Add(pod)
+	}
+	for _, pod := range newPodList(1, v1.PodRunning, tfJob, labelWorker, 1, t) {
+		podIndexer.Add(pod)
+	}
+
+	if _, err := ctr.syncTFJob(getKey(tfJob, t)); err != nil {
+		t.Fatalf("unexpected error when syncing job: %v", err)
+	}
+	if updated == nil || isSucceeded(updated) {
+		t.Errorf("Expected TFJob to not be Succeeded while worker-1 is still running")
+	}
+
+	// Once both workers succeed, the job should complete.
+	secondWorker := newPod(tfJob, labelWorker, 1, t)
+	secondWorker.Status.Phase = v1.PodSucceeded
+	if err := podIndexer.Update(secondWorker); err != nil {
+		t.Fatalf("Failed to update pod: %v", err)
+	}
+	if _, err := ctr.syncTFJob(getKey(tfJob, t)); err != nil {
+		t.Fatalf("unexpected error when syncing job: %v", err)
+	}
+	if updated == nil || !isSucceeded(updated) {
+		t.Errorf("Expected TFJob to be Succeeded once every worker succeeds")
+	}
+}
+
+// TestJobWithChiefNotSucceededWithoutChiefPod verifies that a TFJob which
+// declares a Chief replica is not marked Succeeded off of worker
+// completions alone while no Chief pod has been observed yet - e.g.
+// because the informer cache hasn't caught up, not because the job is
+// chiefless.
+func TestJobWithChiefNotSucceededWithoutChiefPod(t *testing.T) {
+	ctr, _ := newTestController()
+
+	tfJob := newTFJob(1, 0)
+	tfJob.Spec.TFReplicaSpecs[tfv1alpha2.TFReplicaTypeChief] = &tfv1alpha2.TFReplicaSpec{
+		Replicas:      int32Ptr(1),
+		RestartPolicy: tfv1alpha2.RestartPolicyNever,
+		Template:      newTFReplicaPodTemplate(),
+	}
+
+	var updated *tfv1alpha2.TFJob
+	ctr.updateStatusHandler = func(job *tfv1alpha2.TFJob) error {
+		updated = job
+		return nil
+	}
+	setupTFJob(t, ctr, tfJob)
+
+	// Only the worker pod exists in the informer cache; no Chief pod has
+	// been observed.
+	worker := newPod(tfJob, labelWorker, 0, t)
+	worker.Status.Phase = v1.PodSucceeded
+	ctr.podInformer.GetIndexer().Add(worker)
+
+	if _, err := ctr.syncTFJob(getKey(tfJob, t)); err != nil {
+		t.Fatalf("unexpected error when syncing job: %v", err)
+	}
+	if updated != nil && isSucceeded(updated) {
+		t.Errorf("Expected TFJob with a configured Chief to not be Succeeded until the Chief pod succeeds")
+	}
+}
+
+func TestFailurePolicyFail(t *testing.T) {
+	ctr, _ := newTestController()
+
+	tfJob := newTFJob(1, 0)
+	tfJob.Spec.TFReplicaSpecs[tfv1alpha2.TFReplicaTypeWorker].FailurePolicy = tfv1alpha2.FailurePolicyFail
+
+	var updated *tfv1alpha2.TFJob
+	ctr.updateStatusHandler = func(job *tfv1alpha2.TFJob) error {
+		updated = job
+		return nil
+	}
+	setupTFJob(t, ctr, tfJob)
+
+	pod := newPod(tfJob, labelWorker, 0, t)
+	pod.Status.Phase = v1.PodFailed
+	ctr.podInformer.GetIndexer().Add(pod)
+
+	if _, err := ctr.syncTFJob(getKey(tfJob, t)); err != nil {
+		t.Fatalf("unexpected error when syncing job: %v", err)
+	}
+	if updated == nil || !isFailed(updated) {
+		t.Errorf("Expected TFJob to be marked Failed when FailurePolicy is Fail")
+	}
+}
+
+func TestFailurePolicyRestartAll(t *testing.T) {
+	ctr, fakePodControl := newTestController()
+
+	tfJob := newTFJob(2, 0)
+	tfJob.Spec.TFReplicaSpecs[tfv1alpha2.TFReplicaTypeWorker].FailurePolicy = tfv1alpha2.FailurePolicyRestartAll
+	setupTFJob(t, ctr, tfJob)
+
+	podIndexer := ctr.podInformer.GetIndexer()
+	failedPod := newPod(tfJob, labelWorker, 0, t)
+	failedPod.Status.Phase = v1.PodFailed
+	podIndexer.Add(failedPod)
+	okPod := newPod(tfJob, labelWorker, 1, t)
+	okPod.Status.Phase = v1.PodRunning
+	podIndexer.Add(okPod)
+
+	if _, err := ctr.syncTFJob(getKey(tfJob, t)); err != nil {
+		t.Fatalf("unexpected error when syncing job: %v", err)
+	}
+
+	deleted := map[string]bool{}
+	for _, name := range fakePodControl.DeletePodName {
+		deleted[name] = true
+	}
+	if !deleted[failedPod.Name] || !deleted[okPod.Name] {
+		t.Errorf("Expected FailurePolicyRestartAll to delete every pod, got deletions: %v", fakePodControl.DeletePodName)
+	}
+}
+
+func TestFailurePolicyFailTakesPrecedenceOverRestartAll(t *testing.T) {
+	ctr, fakePodControl := newTestController()
+
+	tfJob := newTFJob(1, 1)
+	tfJob.Spec.TFReplicaSpecs[tfv1alpha2.TFReplicaTypePS].FailurePolicy = tfv1alpha2.FailurePolicyFail
+	tfJob.Spec.TFReplicaSpecs[tfv1alpha2.TFReplicaTypeWorker].FailurePolicy = tfv1alpha2.FailurePolicyRestartAll
+	setupTFJob(t, ctr, tfJob)
+
+	var updated *tfv1alpha2.TFJob
+	ctr.updateStatusHandler = func(job *tfv1alpha2.TFJob) error {
+		updated = job
+		return nil
+	}
+
+	// Both a PS (Fail) and a Worker (RestartAll) replica have failed in the
+	// same sync. Regardless of tfReplicaTypes iteration order, Fail must
+	// win: the job is marked Failed and no pod is deleted to restart it.
+	failedWorker := newPod(tfJob, labelWorker, 0, t)
+	failedWorker.Status.Phase = v1.PodFailed
+	ctr.podInformer.GetIndexer().Add(failedWorker)
+	failedPS := newPod(tfJob, labelPS, 0, t)
+	failedPS.Status.Phase = v1.PodFailed
+	ctr.podInformer.GetIndexer().Add(failedPS)
+
+	if _, err := ctr.syncTFJob(getKey(tfJob, t)); err != nil {
+		t.Fatalf("unexpected error when syncing job: %v", err)
+	}
+	if updated == nil || !isFailed(updated) {
+		t.Errorf("Expected TFJob to be marked Failed when any replica's FailurePolicy is Fail")
+	}
+	if len(fakePodControl.DeletePodName) != 0 {
+		t.Errorf("Expected no pods to be deleted for a restart once FailurePolicyFail has failed the job, got deletions: %v", fakePodControl.DeletePodName)
+	}
+}
+
+func TestFailurePolicyIgnore(t *testing.T) {
+	ctr, fakePodControl := newTestController()
+
+	tfJob := newTFJob(1, 0)
+	tfJob.Spec.TFReplicaSpecs[tfv1alpha2.TFReplicaTypeWorker].RestartPolicy = tfv1alpha2.RestartPolicyOnFailure
+	tfJob.Spec.TFReplicaSpecs[tfv1alpha2.TFReplicaTypeWorker].FailurePolicy = tfv1alpha2.FailurePolicyIgnore
+	setupTFJob(t, ctr, tfJob)
+
+	failedPod := newPod(tfJob, labelWorker, 0, t)
+	failedPod.Status.Phase = v1.PodFailed
+	ctr.podInformer.GetIndexer().Add(failedPod)
+
+	if _, err := ctr.syncTFJob(getKey(tfJob, t)); err != nil {
+		t.Fatalf("unexpected error when syncing job: %v", err)
+	}
+	if len(fakePodControl.DeletePodName) != 0 {
+		t.Errorf("Expected FailurePolicyIgnore to leave the failed pod alone, got deletions: %v", fakePodControl.DeletePodName)
+	}
+}
+
+// TestFailurePolicyUnsetFallsBackToRestartPolicy verifies that leaving
+// FailurePolicy unset does not implicitly behave like RestartAll: a failed
+// pod should only be recreated according to the replica's own
+// RestartPolicy, and no other replica's pods should be touched.
+func TestFailurePolicyUnsetFallsBackToRestartPolicy(t *testing.T) {
+	ctr, fakePodControl := newTestController()
+
+	tfJob := newTFJob(2, 0)
+	tfJob.Spec.TFReplicaSpecs[tfv1alpha2.TFReplicaTypeWorker].RestartPolicy = tfv1alpha2.RestartPolicyOnFailure
+	setupTFJob(t, ctr, tfJob)
+
+	podIndexer := ctr.podInformer.GetIndexer()
+	failedPod := newPod(tfJob, labelWorker, 0, t)
+	failedPod.Status.Phase = v1.PodFailed
+	podIndexer.Add(failedPod)
+	okPod := newPod(tfJob, labelWorker, 1, t)
+	okPod.Status.Phase = v1.PodRunning
+	podIndexer.Add(okPod)
+
+	if _, err := ctr.syncTFJob(getKey(tfJob, t)); err != nil {
+		t.Fatalf("unexpected error when syncing job: %v", err)
+	}
+
+	deleted := map[string]bool{}
+	for _, name := range fakePodControl.DeletePodName {
+		deleted[name] = true
+	}
+	if !deleted[failedPod.Name] {
+		t.Errorf("Expected the failed pod to be recreated per its RestartPolicy, got deletions: %v", fakePodControl.DeletePodName)
+	}
+	if deleted[okPod.Name] {
+		t.Errorf("Expected FailurePolicy unset to only restart the failed pod, not every pod, got deletions: %v", fakePodControl.DeletePodName)
+	}
+}
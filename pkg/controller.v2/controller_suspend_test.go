@@ -0,0 +1,87 @@
+// Copyright 2018 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	tfv1alpha2 "github.com/kubeflow/tf-operator/pkg/apis/tensorflow/v1alpha2"
+)
+
+func TestSuspendTearsDownPodsAndUnsuspendRecreatesThem(t *testing.T) {
+	ctr, fakePodControl := newTestController()
+
+	tfJob := newTFJob(1, 0)
+	startTime := metav1.NewTime(time.Now().Add(-time.Minute))
+	tfJob.Status.StartTime = &startTime
+
+	var updated *tfv1alpha2.TFJob
+	ctr.updateStatusHandler = func(job *tfv1alpha2.TFJob) error {
+		updated = job
+		return nil
+	}
+	setupTFJob(t, ctr, tfJob)
+
+	pod := newPod(tfJob, labelWorker, 0, t)
+	pod.Status.Phase = v1.PodRunning
+	ctr.podInformer.GetIndexer().Add(pod)
+
+	suspend := true
+	tfJob.Spec.Suspend = &suspend
+	setupTFJob(t, ctr, tfJob)
+	if _, err := ctr.syncTFJob(getKey(tfJob, t)); err != nil {
+		t.Fatalf("unexpected error when syncing suspended job: %v", err)
+	}
+
+	if len(fakePodControl.DeletePodName) != 1 || fakePodControl.DeletePodName[0] != pod.Name {
+		t.Errorf("Expected suspend to delete pod %s, got deletions: %v", pod.Name, fakePodControl.DeletePodName)
+	}
+	if updated == nil || updated.Status.StartTime != nil {
+		t.Errorf("Expected StartTime to be cleared while suspended")
+	}
+	if !hasConditionReason(updated, tfv1alpha2.TFJobSuspended, "TFJobSuspended") {
+		t.Errorf("Expected a Suspended condition")
+	}
+
+	// A resync while still suspended and with no pods left must stay
+	// quiescent: no further deletions, no error.
+	ctr.podInformer.GetIndexer().Delete(pod)
+	deletionsBefore := len(fakePodControl.DeletePodName)
+	if _, err := ctr.syncTFJob(getKey(tfJob, t)); err != nil {
+		t.Fatalf("unexpected error resyncing suspended job: %v", err)
+	}
+	if len(fakePodControl.DeletePodName) != deletionsBefore {
+		t.Errorf("Expected no new deletions on a quiescent suspended resync")
+	}
+
+	// Unsuspending recreates the pod and sets a fresh StartTime.
+	suspend = false
+	tfJob.Spec.Suspend = &suspend
+	tfJob.Status.StartTime = nil
+	setupTFJob(t, ctr, tfJob)
+	if _, err := ctr.syncTFJob(getKey(tfJob, t)); err != nil {
+		t.Fatalf("unexpected error when syncing unsuspended job: %v", err)
+	}
+	if len(fakePodControl.Templates) == 0 {
+		t.Errorf("Expected unsuspend to recreate pods")
+	}
+	if updated == nil || updated.Status.StartTime == nil {
+		t.Errorf("Expected a fresh StartTime once unsuspended")
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2018 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	tfv1alpha2 "github.com/kubeflow/tf-operator/pkg/apis/tensorflow/v1alpha2"
+)
+
+const (
+	testTFJobName      = "test-tfjob"
+	testTFJobNamespace = "default"
+
+	threadCount = 1
+)
+
+// alwaysReady is used to stub out *Synced fields on the controller so unit
+// tests don't need a real informer to finish its initial sync.
+var alwaysReady = func() bool { return true }
+
+func int32Ptr(i int32) *int32 { return &i }
+
+// newTFJob builds a minimal TFJob with numWorkers Worker replicas and, if
+// numPS > 0, numPS PS replicas, for use across this package's tests.
+func newTFJob(numWorkers, numPS int32) *tfv1alpha2.TFJob {
+	tfJob := &tfv1alpha2.TFJob{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       controllerKind.Kind,
+			APIVersion: tfv1alpha2.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testTFJobName,
+			Namespace: testTFJobNamespace,
+			UID:       types.UID("test-tfjob-uid"),
+		},
+		Spec: tfv1alpha2.TFJobSpec{
+			TFReplicaSpecs: map[tfv1alpha2.TFReplicaType]*tfv1alpha2.TFReplicaSpec{
+				tfv1alpha2.TFReplicaTypeWorker: {
+					Replicas:      int32Ptr(numWorkers),
+					RestartPolicy: tfv1alpha2.RestartPolicyNever,
+					Template:      newTFReplicaPodTemplate(),
+				},
+			},
+		},
+	}
+	if numPS > 0 {
+		tfJob.Spec.TFReplicaSpecs[tfv1alpha2.TFReplicaTypePS] = &tfv1alpha2.TFReplicaSpec{
+			Replicas:      int32Ptr(numPS),
+			RestartPolicy: tfv1alpha2.RestartPolicyNever,
+			Template:      newTFReplicaPodTemplate(),
+		}
+	}
+	return tfJob
+}
+
+func newTFReplicaPodTemplate() v1.PodTemplateSpec {
+	return v1.PodTemplateSpec{
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{
+					Name:  tfv1alpha2.DefaultContainerName,
+					Image: "kubeflow/tf-dist-mnist-test:1.0",
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,343 @@
+// Copyright 2018 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+
+	tfv1alpha2 "github.com/kubeflow/tf-operator/pkg/apis/tensorflow/v1alpha2"
+)
+
+const (
+	// tfJobNameLabel and the two labels below are stamped on every pod and
+	// service the controller creates, so it can find them again through the
+	// pod/service informers' indexers.
+	tfJobNameLabel      = "tf-job-name"
+	tfReplicaTypeLabel  = "tf-replica-type"
+	tfReplicaIndexLabel = "tf-replica-index"
+
+	// labelGroupName is the label used to mark resources as owned by this
+	// operator.
+	labelGroupName = "group-name"
+
+	labelWorker = "worker"
+	labelPS     = "ps"
+	labelChief  = "chief"
+	labelMaster = "master"
+	labelEval   = "evaluator"
+
+	// envTFConfig is the name of the environment variable the tensorflow
+	// container reads its cluster spec from.
+	envTFConfig = "TF_CONFIG"
+)
+
+// genLabels returns the base set of labels stamped on every pod/service
+// created for the TFJob identified by jobKey (a namespace/name workqueue
+// key).
+func genLabels(jobKey string) map[string]string {
+	jobName := jobKey
+	if parts := strings.Split(jobKey, "/"); len(parts) == 2 {
+		jobName = parts[1]
+	}
+	return map[string]string{
+		labelGroupName: tfv1alpha2.GroupName,
+		tfJobNameLabel: strings.Replace(jobName, "/", "-", -1),
+	}
+}
+
+// tfReplicaTypeLower lowercases a TFReplicaType for use in labels, pod
+// names and the TF_CONFIG cluster spec, e.g. TFReplicaTypeWorker -> "worker".
+func tfReplicaTypeLower(rtype tfv1alpha2.TFReplicaType) string {
+	return strings.ToLower(string(rtype))
+}
+
+// getPodsForTFJob returns the pods owned by tfJob, read from the pod
+// informer's indexer.
+func (c *TFController) getPodsForTFJob(tfJob *tfv1alpha2.TFJob) ([]*v1.Pod, error) {
+	selector := labels.SelectorFromSet(genLabels(getKeyForObj(tfJob)))
+	objs, err := c.podInformer.GetIndexer().ByIndex(cache.NamespaceIndex, tfJob.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	var pods []*v1.Pod
+	for _, obj := range objs {
+		pod, ok := obj.(*v1.Pod)
+		if !ok {
+			continue
+		}
+		if !selector.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		pods = append(pods, pod)
+	}
+	return pods, nil
+}
+
+// groupPodsByReplicaType buckets pods by their tf-replica-type label.
+func groupPodsByReplicaType(pods []*v1.Pod) map[tfv1alpha2.TFReplicaType][]*v1.Pod {
+	grouped := make(map[tfv1alpha2.TFReplicaType][]*v1.Pod)
+	for _, pod := range pods {
+		rt := pod.Labels[tfReplicaTypeLabel]
+		for _, replicaType := range tfReplicaTypes {
+			if tfReplicaTypeLower(replicaType) == rt {
+				grouped[replicaType] = append(grouped[replicaType], pod)
+			}
+		}
+	}
+	return grouped
+}
+
+// tfReplicaTypes lists every TFReplicaType the controller knows how to
+// schedule, in a fixed order used whenever a TFJobSpec's TFReplicaSpecs map
+// must be iterated deterministically (map iteration order is randomized per
+// run and would otherwise make things like FailurePolicy precedence
+// nondeterministic).
+var tfReplicaTypes = []tfv1alpha2.TFReplicaType{
+	tfv1alpha2.TFReplicaTypePS,
+	tfv1alpha2.TFReplicaTypeWorker,
+	tfv1alpha2.TFReplicaTypeChief,
+	tfv1alpha2.TFReplicaTypeMaster,
+	tfv1alpha2.TFReplicaTypeEval,
+}
+
+// reconcilePods creates any pods of type rtype that are missing relative to
+// spec.Replicas, and applies spec.RestartPolicy to pods that have already
+// exited.
+func (c *TFController) reconcilePods(tfJob *tfv1alpha2.TFJob, rtype tfv1alpha2.TFReplicaType, spec *tfv1alpha2.TFReplicaSpec, pods []*v1.Pod) error {
+	rt := tfReplicaTypeLower(rtype)
+	replicas := int32(1)
+	if spec.Replicas != nil {
+		replicas = *spec.Replicas
+	}
+
+	byIndex := make(map[int32]*v1.Pod, len(pods))
+	for _, pod := range pods {
+		index, err := strconv.Atoi(pod.Labels[tfReplicaIndexLabel])
+		if err != nil {
+			continue
+		}
+		byIndex[int32(index)] = pod
+	}
+
+	for index := int32(0); index < replicas; index++ {
+		pod, exists := byIndex[index]
+		if !exists {
+			if err := c.createNewPod(tfJob, rt, fmt.Sprintf("%d", index), spec); err != nil {
+				return err
+			}
+			continue
+		}
+		if pod.Status.Phase != v1.PodFailed {
+			continue
+		}
+		if err := c.handleFailedPod(tfJob, rt, fmt.Sprintf("%d", index), spec, pod); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handleFailedPod decides, based on spec.RestartPolicy, whether a failed
+// pod should be deleted and recreated.
+func (c *TFController) handleFailedPod(tfJob *tfv1alpha2.TFJob, rt, index string, spec *tfv1alpha2.TFReplicaSpec, pod *v1.Pod) error {
+	if effectiveFailurePolicy(spec) == tfv1alpha2.FailurePolicyIgnore {
+		return nil
+	}
+
+	restart := false
+	switch spec.RestartPolicy {
+	case tfv1alpha2.RestartPolicyAlways:
+		restart = true
+	case tfv1alpha2.RestartPolicyOnFailure:
+		restart = true
+	case tfv1alpha2.RestartPolicyExitCode:
+		restart = shouldRestartOnExitCode(pod)
+	case tfv1alpha2.RestartPolicyNever:
+		restart = false
+	}
+
+	if !restart {
+		return nil
+	}
+
+	if err := c.podControl.DeletePod(pod.Namespace, pod.Name, tfJob); err != nil {
+		return err
+	}
+	return c.createNewPod(tfJob, rt, index, spec)
+}
+
+// shouldRestartOnExitCode inspects the tensorflow container's terminated
+// exit code: 0 means success (no restart needed), 130/137/143 are signals
+// sent by Kubernetes itself when evicting or OOM-killing a pod and are
+// treated as transient, everything else is a permanent failure.
+func shouldRestartOnExitCode(pod *v1.Pod) bool {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name != tfv1alpha2.DefaultContainerName {
+			continue
+		}
+		if status.State.Terminated == nil {
+			return false
+		}
+		switch status.State.Terminated.ExitCode {
+		case 0:
+			return false
+		case 130, 137, 143:
+			return true
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+// createNewPod creates the pod for replica type rt, index, of tfJob.
+func (c *TFController) createNewPod(tfJob *tfv1alpha2.TFJob, rt, index string, spec *tfv1alpha2.TFReplicaSpec) error {
+	jobKey := getKeyForObj(tfJob)
+	podLabels := genLabels(jobKey)
+	podLabels[tfReplicaTypeLabel] = rt
+	podLabels[tfReplicaIndexLabel] = index
+
+	podTemplate := spec.Template.DeepCopy()
+	if podTemplate.Labels == nil {
+		podTemplate.Labels = make(map[string]string)
+	}
+	for k, v := range podLabels {
+		podTemplate.Labels[k] = v
+	}
+
+	if err := setClusterSpec(podTemplate, tfJob, rt, index); err != nil {
+		return err
+	}
+	setRestartPolicy(podTemplate, spec)
+	c.setGangScheduling(podTemplate, tfJob)
+
+	return c.podControl.CreatePodsWithControllerRef(tfJob.Namespace, podTemplate, tfJob, asOwnerRef(tfJob))
+}
+
+// setGangScheduling stamps the batch scheduler's group-name annotation and
+// (if configured) SchedulingPolicy.SchedulerName onto podTemplateSpec, so the
+// scheduler places it as part of tfJob's PodGroup. It is a no-op unless gang
+// scheduling is enabled on the controller.
+func (c *TFController) setGangScheduling(podTemplateSpec *v1.PodTemplateSpec, tfJob *tfv1alpha2.TFJob) {
+	if !c.enableGangScheduling {
+		return
+	}
+
+	if podTemplateSpec.Annotations == nil {
+		podTemplateSpec.Annotations = make(map[string]string)
+	}
+	podTemplateSpec.Annotations[groupNameAnnotation] = podGroupName(tfJob)
+
+	if policy := tfJob.Spec.SchedulingPolicy; policy != nil && policy.SchedulerName != "" {
+		podTemplateSpec.Spec.SchedulerName = policy.SchedulerName
+	}
+}
+
+// setClusterSpec injects the TF_CONFIG environment variable describing the
+// cluster into the tensorflow container of podTemplateSpec. For a
+// chiefless job (see tfv1alpha2.IsChieflessJob) this does not synthesize a
+// fake chief entry: workers are always given task type "worker", and
+// worker:0 is only special to the controller's own completion tracking,
+// not to the cluster spec TensorFlow sees.
+func setClusterSpec(podTemplateSpec *v1.PodTemplateSpec, tfJob *tfv1alpha2.TFJob, rt, index string) error {
+	cluster, err := genClusterSpec(tfJob)
+	if err != nil {
+		return err
+	}
+
+	taskIndex, err := strconv.Atoi(index)
+	if err != nil {
+		return err
+	}
+
+	tfConfig := tfConfig{
+		Cluster: cluster,
+		Task: tfConfigTask{
+			Type:  rt,
+			Index: taskIndex,
+		},
+	}
+
+	raw, err := json.Marshal(tfConfig)
+	if err != nil {
+		return err
+	}
+
+	for i := range podTemplateSpec.Spec.Containers {
+		container := &podTemplateSpec.Spec.Containers[i]
+		if container.Name != tfv1alpha2.DefaultContainerName {
+			continue
+		}
+		container.Env = append(container.Env, v1.EnvVar{Name: envTFConfig, Value: string(raw)})
+	}
+	return nil
+}
+
+// tfConfig mirrors the JSON structure TensorFlow's
+// tf.distribute/TF_CONFIG expects.
+type tfConfig struct {
+	Cluster map[string][]string `json:"cluster"`
+	Task    tfConfigTask        `json:"task"`
+}
+
+type tfConfigTask struct {
+	Type  string `json:"type"`
+	Index int    `json:"index"`
+}
+
+// genClusterSpec builds the "cluster" portion of TF_CONFIG: a map from
+// lowercased replica type to the list of
+// "<job>-<type>-<index>.<ns>.svc.cluster.local:<port>" endpoints (each
+// replica's headless service DNS name) for that type, in index order.
+func genClusterSpec(tfJob *tfv1alpha2.TFJob) (map[string][]string, error) {
+	cluster := make(map[string][]string)
+	for rtype, spec := range tfJob.Spec.TFReplicaSpecs {
+		rt := tfReplicaTypeLower(rtype)
+		replicas := int32(1)
+		if spec.Replicas != nil {
+			replicas = *spec.Replicas
+		}
+		endpoints := make([]string, 0, replicas)
+		for i := int32(0); i < replicas; i++ {
+			endpoints = append(endpoints, fmt.Sprintf("%s-%s-%d.%s.svc.cluster.local:%d",
+				tfJob.Name, rt, i, tfJob.Namespace, tfv1alpha2.DefaultPort))
+		}
+		cluster[rt] = endpoints
+	}
+	return cluster, nil
+}
+
+// setRestartPolicy translates a TFReplicaSpec's RestartPolicy into the
+// corresponding v1.RestartPolicy on the pod template. ExitCode-based
+// restarts are implemented by the controller deleting and recreating
+// failed pods itself, so the pod's own RestartPolicy must be Never in that
+// case to keep kubelet from racing the controller.
+func setRestartPolicy(podTemplateSpec *v1.PodTemplateSpec, spec *tfv1alpha2.TFReplicaSpec) {
+	switch spec.RestartPolicy {
+	case tfv1alpha2.RestartPolicyAlways:
+		podTemplateSpec.Spec.RestartPolicy = v1.RestartPolicyAlways
+	case tfv1alpha2.RestartPolicyOnFailure:
+		podTemplateSpec.Spec.RestartPolicy = v1.RestartPolicyOnFailure
+	default:
+		podTemplateSpec.Spec.RestartPolicy = v1.RestartPolicyNever
+	}
+}
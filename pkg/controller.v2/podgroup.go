@@ -0,0 +1,146 @@
+// Copyright 2018 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"reflect"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	volcanov1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	vcclientset "volcano.sh/apis/pkg/client/clientset/versioned"
+
+	tfv1alpha2 "github.com/kubeflow/tf-operator/pkg/apis/tensorflow/v1alpha2"
+)
+
+// groupNameAnnotation is the annotation the batch scheduler reads off each
+// pod to learn which PodGroup it belongs to.
+const groupNameAnnotation = "scheduling.k8s.io/group-name"
+
+// PodGroupControlInterface is the analogue of
+// k8s.io/kubernetes/pkg/controller.PodControlInterface for the PodGroup this
+// controller creates ahead of a TFJob's pods when gang scheduling is
+// enabled.
+type PodGroupControlInterface interface {
+	GetPodGroup(namespace, name string) (*volcanov1beta1.PodGroup, error)
+	CreatePodGroup(podGroup *volcanov1beta1.PodGroup) (*volcanov1beta1.PodGroup, error)
+	UpdatePodGroup(podGroup *volcanov1beta1.PodGroup) (*volcanov1beta1.PodGroup, error)
+}
+
+// RealPodGroupControl is the production PodGroupControlInterface, backed by
+// a real volcano clientset.
+type RealPodGroupControl struct {
+	VolcanoClient vcclientset.Interface
+}
+
+func (r RealPodGroupControl) GetPodGroup(namespace, name string) (*volcanov1beta1.PodGroup, error) {
+	return r.VolcanoClient.SchedulingV1beta1().PodGroups(namespace).Get(name, metav1.GetOptions{})
+}
+
+func (r RealPodGroupControl) CreatePodGroup(podGroup *volcanov1beta1.PodGroup) (*volcanov1beta1.PodGroup, error) {
+	return r.VolcanoClient.SchedulingV1beta1().PodGroups(podGroup.Namespace).Create(podGroup)
+}
+
+func (r RealPodGroupControl) UpdatePodGroup(podGroup *volcanov1beta1.PodGroup) (*volcanov1beta1.PodGroup, error) {
+	return r.VolcanoClient.SchedulingV1beta1().PodGroups(podGroup.Namespace).Update(podGroup)
+}
+
+// podGroupReady reports whether podGroup's status shows the batch scheduler
+// has admitted enough resources to run it, i.e. pod creation is safe to
+// proceed.
+func podGroupReady(podGroup *volcanov1beta1.PodGroup) bool {
+	switch podGroup.Status.Phase {
+	case volcanov1beta1.PodGroupInqueue, volcanov1beta1.PodGroupRunning:
+		return true
+	default:
+		return false
+	}
+}
+
+// podGroupName derives the PodGroup's name from tfJob's, so it can be looked
+// up deterministically without tracking a separate reference.
+func podGroupName(tfJob *tfv1alpha2.TFJob) string {
+	return tfJob.Name
+}
+
+// totalReplicas sums Replicas across every TFReplicaSpec of tfJob, the
+// default MinMember for its PodGroup.
+func totalReplicas(tfJob *tfv1alpha2.TFJob) int32 {
+	var total int32
+	for _, spec := range tfJob.Spec.TFReplicaSpecs {
+		replicas := int32(1)
+		if spec.Replicas != nil {
+			replicas = *spec.Replicas
+		}
+		total += replicas
+	}
+	return total
+}
+
+// desiredPodGroupSpec derives the PodGroupSpec a TFJob's PodGroup should
+// have from tfJob.Spec.SchedulingPolicy, defaulting MinMember to the sum of
+// all replicas.
+func desiredPodGroupSpec(tfJob *tfv1alpha2.TFJob) volcanov1beta1.PodGroupSpec {
+	minAvailable := totalReplicas(tfJob)
+	var queue, priorityClass string
+	if policy := tfJob.Spec.SchedulingPolicy; policy != nil {
+		if policy.MinAvailable != nil {
+			minAvailable = *policy.MinAvailable
+		}
+		queue = policy.Queue
+		priorityClass = policy.PriorityClass
+	}
+	return volcanov1beta1.PodGroupSpec{
+		MinMember:         minAvailable,
+		Queue:             queue,
+		PriorityClassName: priorityClass,
+	}
+}
+
+// syncPodGroup ensures a PodGroup owned by tfJob exists and matches
+// tfJob.Spec.SchedulingPolicy: it creates the PodGroup the first time it is
+// seen, and reconciles its spec against the TFJob's current
+// SchedulingPolicy on every subsequent sync so that later changes to
+// MinAvailable/Queue/PriorityClass are not silently ignored. The PodGroup is
+// left to the Kubernetes garbage collector to clean up via its owner
+// reference once tfJob is deleted.
+func (c *TFController) syncPodGroup(tfJob *tfv1alpha2.TFJob) (*volcanov1beta1.PodGroup, error) {
+	name := podGroupName(tfJob)
+	desiredSpec := desiredPodGroupSpec(tfJob)
+
+	podGroup, err := c.podGroupControl.GetPodGroup(tfJob.Namespace, name)
+	if err == nil {
+		if reflect.DeepEqual(podGroup.Spec, desiredSpec) {
+			return podGroup, nil
+		}
+		updated := podGroup.DeepCopy()
+		updated.Spec = desiredSpec
+		return c.podGroupControl.UpdatePodGroup(updated)
+	}
+	if !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	podGroup = &volcanov1beta1.PodGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       tfJob.Namespace,
+			Labels:          genLabels(getKeyForObj(tfJob)),
+			OwnerReferences: []metav1.OwnerReference{asOwner(tfJob)},
+		},
+		Spec: desiredSpec,
+	}
+	return c.podGroupControl.CreatePodGroup(podGroup)
+}
@@ -0,0 +1,109 @@
+// Copyright 2018 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	kubeclientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+
+	tfv1alpha2 "github.com/kubeflow/tf-operator/pkg/apis/tensorflow/v1alpha2"
+)
+
+// ServiceControlInterface is the analogue of
+// k8s.io/kubernetes/pkg/controller.PodControlInterface for the headless
+// services the controller creates so pods of one replica type can address
+// pods of another by a stable DNS name.
+type ServiceControlInterface interface {
+	CreateServicesWithControllerRef(namespace string, service *v1.Service, object metav1.Object, controllerRef *metav1.OwnerReference) error
+	DeleteService(namespace, serviceID string, object metav1.Object) error
+}
+
+// RealServiceControl is the production ServiceControlInterface, backed by a
+// real Kubernetes clientset.
+type RealServiceControl struct {
+	KubeClient kubeclientset.Interface
+	Recorder   record.EventRecorder
+}
+
+func (r RealServiceControl) CreateServicesWithControllerRef(namespace string, service *v1.Service, object metav1.Object, controllerRef *metav1.OwnerReference) error {
+	svc := service.DeepCopy()
+	svc.OwnerReferences = append(svc.OwnerReferences, *controllerRef)
+	_, err := r.KubeClient.CoreV1().Services(namespace).Create(svc)
+	return err
+}
+
+func (r RealServiceControl) DeleteService(namespace, serviceID string, object metav1.Object) error {
+	err := r.KubeClient.CoreV1().Services(namespace).Delete(serviceID, &metav1.DeleteOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// getServicesForTFJob returns the headless services owned by tfJob, read
+// from the service informer's indexer.
+func (c *TFController) getServicesForTFJob(tfJob *tfv1alpha2.TFJob) ([]*v1.Service, error) {
+	selector := labels.SelectorFromSet(genLabels(getKeyForObj(tfJob)))
+	objs, err := c.serviceInformer.GetIndexer().ByIndex(cache.NamespaceIndex, tfJob.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	var services []*v1.Service
+	for _, obj := range objs {
+		svc, ok := obj.(*v1.Service)
+		if !ok {
+			continue
+		}
+		if !selector.Matches(labels.Set(svc.Labels)) {
+			continue
+		}
+		services = append(services, svc)
+	}
+	return services, nil
+}
+
+// createNewService creates the headless service for replica type rt, index,
+// of tfJob, so other replicas can reach it at a stable DNS name.
+func (c *TFController) createNewService(tfJob *tfv1alpha2.TFJob, rt, index string) error {
+	svcLabels := genLabels(getKeyForObj(tfJob))
+	svcLabels[tfReplicaTypeLabel] = rt
+	svcLabels[tfReplicaIndexLabel] = index
+
+	svc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      serviceName(tfJob, rt, index),
+			Namespace: tfJob.Namespace,
+			Labels:    svcLabels,
+		},
+		Spec: v1.ServiceSpec{
+			ClusterIP: v1.ClusterIPNone,
+			Selector:  svcLabels,
+			Ports: []v1.ServicePort{
+				{Name: tfv1alpha2.DefaultPortName, Port: tfv1alpha2.DefaultPort},
+			},
+		},
+	}
+
+	return c.serviceControl.CreateServicesWithControllerRef(tfJob.Namespace, svc, tfJob, asOwnerRef(tfJob))
+}
+
+func serviceName(tfJob *tfv1alpha2.TFJob, rt, index string) string {
+	return tfJob.Name + "-" + rt + "-" + index
+}
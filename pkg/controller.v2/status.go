@@ -0,0 +1,219 @@
+// Copyright 2018 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"time"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	tfv1alpha2 "github.com/kubeflow/tf-operator/pkg/apis/tensorflow/v1alpha2"
+)
+
+const (
+	// ReasonDeadlineExceeded is the TFJobFailed reason used when a TFJob
+	// runs longer than Spec.ActiveDeadlineSeconds.
+	ReasonDeadlineExceeded = "DeadlineExceeded"
+	// ReasonBackoffLimitExceeded is the TFJobFailed reason used when more
+	// than Spec.BackoffLimit pods have failed.
+	ReasonBackoffLimitExceeded = "BackoffLimitExceeded"
+)
+
+// newTFJobCondition creates a new TFJobCondition in status True.
+func newTFJobCondition(condType tfv1alpha2.TFJobConditionType, reason, message string) tfv1alpha2.TFJobCondition {
+	now := metav1.Now()
+	return tfv1alpha2.TFJobCondition{
+		Type:               condType,
+		Status:             v1.ConditionTrue,
+		Reason:             reason,
+		Message:            message,
+		LastUpdateTime:     now,
+		LastTransitionTime: now,
+	}
+}
+
+// updateTFJobConditions appends (or refreshes) a condition of the given
+// type on tfJob's status. Terminal condition types (Succeeded/Failed) are
+// mutually exclusive with each other and with Running.
+func updateTFJobConditions(tfJob *tfv1alpha2.TFJob, condType tfv1alpha2.TFJobConditionType, reason, message string) {
+	condition := newTFJobCondition(condType, reason, message)
+	if condType == tfv1alpha2.TFJobSucceeded || condType == tfv1alpha2.TFJobFailed {
+		for i, existing := range tfJob.Status.Conditions {
+			switch existing.Type {
+			case tfv1alpha2.TFJobRunning, tfv1alpha2.TFJobSucceeded, tfv1alpha2.TFJobFailed:
+				if existing.Type != condType && existing.Status == v1.ConditionTrue {
+					tfJob.Status.Conditions[i].Status = v1.ConditionFalse
+					tfJob.Status.Conditions[i].LastTransitionTime = condition.LastTransitionTime
+				}
+			}
+		}
+	}
+	for i, existing := range tfJob.Status.Conditions {
+		if existing.Type != condType {
+			continue
+		}
+		if existing.Status != v1.ConditionTrue {
+			tfJob.Status.Conditions[i] = condition
+			return
+		}
+		if existing.Reason == reason && existing.Message == message {
+			return
+		}
+		condition.LastTransitionTime = existing.LastTransitionTime
+		tfJob.Status.Conditions[i] = condition
+		return
+	}
+	tfJob.Status.Conditions = append(tfJob.Status.Conditions, condition)
+}
+
+// clearTFJobCondition flips an existing condition of the given type to
+// False, used to retire the Suspended condition once a job unsuspends.
+func clearTFJobCondition(tfJob *tfv1alpha2.TFJob, condType tfv1alpha2.TFJobConditionType) {
+	for i, c := range tfJob.Status.Conditions {
+		if c.Type == condType && c.Status == v1.ConditionTrue {
+			tfJob.Status.Conditions[i].Status = v1.ConditionFalse
+			tfJob.Status.Conditions[i].LastTransitionTime = metav1.Now()
+		}
+	}
+}
+
+func isSucceeded(tfJob *tfv1alpha2.TFJob) bool {
+	return hasCondition(tfJob, tfv1alpha2.TFJobSucceeded)
+}
+
+func isFailed(tfJob *tfv1alpha2.TFJob) bool {
+	return hasCondition(tfJob, tfv1alpha2.TFJobFailed)
+}
+
+func hasCondition(tfJob *tfv1alpha2.TFJob, condType tfv1alpha2.TFJobConditionType) bool {
+	for _, c := range tfJob.Status.Conditions {
+		if c.Type == condType && c.Status == v1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// initializeTFReplicaStatuses ensures tfJob.Status.ReplicaStatuses has an
+// entry for rtype.
+func initializeTFReplicaStatuses(tfJob *tfv1alpha2.TFJob, rtype tfv1alpha2.TFReplicaType) {
+	if tfJob.Status.ReplicaStatuses == nil {
+		tfJob.Status.ReplicaStatuses = make(map[tfv1alpha2.TFReplicaType]*tfv1alpha2.TFReplicaStatus)
+	}
+	if _, ok := tfJob.Status.ReplicaStatuses[rtype]; !ok {
+		tfJob.Status.ReplicaStatuses[rtype] = &tfv1alpha2.TFReplicaStatus{}
+	}
+}
+
+// updateTFJobReplicaStatuses recomputes the Active/Succeeded/Failed counts
+// for rtype from the current state of pods.
+func updateTFJobReplicaStatuses(tfJob *tfv1alpha2.TFJob, rtype tfv1alpha2.TFReplicaType, pods []*v1.Pod) {
+	initializeTFReplicaStatuses(tfJob, rtype)
+	status := &tfv1alpha2.TFReplicaStatus{}
+	for _, pod := range pods {
+		switch pod.Status.Phase {
+		case v1.PodRunning, v1.PodPending:
+			status.Active++
+		case v1.PodSucceeded:
+			status.Succeeded++
+		case v1.PodFailed:
+			status.Failed++
+		}
+	}
+	tfJob.Status.ReplicaStatuses[rtype] = status
+}
+
+// pastActiveDeadline reports whether tfJob has been running for longer
+// than Spec.ActiveDeadlineSeconds.
+func pastActiveDeadline(tfJob *tfv1alpha2.TFJob) bool {
+	if tfJob.Spec.ActiveDeadlineSeconds == nil || tfJob.Status.StartTime == nil {
+		return false
+	}
+	deadline := tfJob.Status.StartTime.Add(time.Duration(*tfJob.Spec.ActiveDeadlineSeconds) * time.Second)
+	return time.Now().After(deadline)
+}
+
+// timeUntilActiveDeadline returns how long remains until tfJob's
+// ActiveDeadlineSeconds elapses, used to requeue the job for re-evaluation
+// at exactly that time.
+func timeUntilActiveDeadline(tfJob *tfv1alpha2.TFJob) time.Duration {
+	if tfJob.Spec.ActiveDeadlineSeconds == nil || tfJob.Status.StartTime == nil {
+		return 0
+	}
+	deadline := tfJob.Status.StartTime.Add(time.Duration(*tfJob.Spec.ActiveDeadlineSeconds) * time.Second)
+	return time.Until(deadline)
+}
+
+// pastBackoffLimit reports whether the number of failed pods, counted
+// across all replica types whose RestartPolicy is OnFailure or ExitCode,
+// exceeds Spec.BackoffLimit.
+func pastBackoffLimit(tfJob *tfv1alpha2.TFJob, podsByType map[tfv1alpha2.TFReplicaType][]*v1.Pod) bool {
+	if tfJob.Spec.BackoffLimit == nil {
+		return false
+	}
+
+	var failed int32
+	for rtype, spec := range tfJob.Spec.TFReplicaSpecs {
+		if spec.RestartPolicy != tfv1alpha2.RestartPolicyOnFailure && spec.RestartPolicy != tfv1alpha2.RestartPolicyExitCode {
+			continue
+		}
+		for _, pod := range podsByType[rtype] {
+			if pod.Status.Phase == v1.PodFailed {
+				failed++
+			}
+		}
+	}
+	return failed > *tfJob.Spec.BackoffLimit
+}
+
+// cleanupTFJob deletes tfJob's owned pods/services according to
+// Spec.CleanPodPolicy now that the job has reached a terminal state.
+// CleanPodPolicyRunning (the default) only removes pods that are not yet
+// terminal; CleanPodPolicyAll removes everything; CleanPodPolicyNone
+// leaves all pods and services in place. forceDeletePods overrides
+// CleanPodPolicy for pods (but not services), used when
+// ActiveDeadlineSeconds elapses: an overrun job's pods must stop running
+// unconditionally, unlike a BackoffLimit failure which honors
+// CleanPodPolicy as usual.
+func (c *TFController) cleanupTFJob(tfJob *tfv1alpha2.TFJob, pods []*v1.Pod, services []*v1.Service, forceDeletePods bool) error {
+	policy := tfv1alpha2.CleanPodPolicyRunning
+	if tfJob.Spec.CleanPodPolicy != nil {
+		policy = *tfJob.Spec.CleanPodPolicy
+	}
+
+	if !forceDeletePods && policy == tfv1alpha2.CleanPodPolicyNone {
+		return nil
+	}
+	for _, pod := range pods {
+		if !forceDeletePods && policy == tfv1alpha2.CleanPodPolicyRunning &&
+			(pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed) {
+			continue
+		}
+		if err := c.podControl.DeletePod(pod.Namespace, pod.Name, tfJob); err != nil {
+			return err
+		}
+	}
+
+	if policy == tfv1alpha2.CleanPodPolicyNone {
+		return nil
+	}
+	for _, svc := range services {
+		if err := c.serviceControl.DeleteService(svc.Namespace, svc.Name, tfJob); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2018 The Kubeflow Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+
+	tfv1alpha2 "github.com/kubeflow/tf-operator/pkg/apis/tensorflow/v1alpha2"
+)
+
+// convertTFJobToUnstructured converts a typed TFJob into its
+// unstructured.Unstructured representation, which tests add to the
+// tfJobInformer's indexer; tfJobFromObj converts it back.
+func convertTFJobToUnstructured(tfJob *tfv1alpha2.TFJob) (*unstructured.Unstructured, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(tfJob)
+	if err != nil {
+		return nil, err
+	}
+	return &unstructured.Unstructured{Object: content}, nil
+}
+
+// tfJobFromObj converts an object retrieved from the tfJobInformer's
+// indexer, which may be either a typed *tfv1alpha2.TFJob or an
+// *unstructured.Unstructured, into a typed *tfv1alpha2.TFJob.
+func tfJobFromObj(obj interface{}) (*tfv1alpha2.TFJob, error) {
+	switch t := obj.(type) {
+	case *tfv1alpha2.TFJob:
+		return t, nil
+	case *unstructured.Unstructured:
+		tfJob := &tfv1alpha2.TFJob{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(t.Object, tfJob); err != nil {
+			return nil, err
+		}
+		return tfJob, nil
+	default:
+		return nil, fmt.Errorf("unknown object type %T in TFJob indexer", obj)
+	}
+}
+
+// getKey builds the namespace/name key used to look up a TFJob in the
+// workqueue and in the informer's indexer.
+func getKey(tfJob *tfv1alpha2.TFJob, errHandler interface{ Errorf(string, ...interface{}) }) string {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(tfJob)
+	if err != nil {
+		errHandler.Errorf("Failed to get the key for TFJob %s: %v", tfJob.Name, err)
+		return ""
+	}
+	return key
+}
+
+// getKeyForObj is getKey without a test-style error handler, for use from
+// non-test production code paths that cannot fail on a well-formed object.
+func getKeyForObj(obj interface{}) string {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		return ""
+	}
+	return key
+}
+
+// splitKey splits a namespace/name workqueue key back into its parts.
+func splitKey(key string) (namespace, name string, err error) {
+	return cache.SplitMetaNamespaceKey(key)
+}